@@ -2,16 +2,22 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/gob"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alexedwards/scs/pgxstore"
 	"github.com/alexedwards/scs/v2"
 	"github.com/erikgmatos/gobid/internal/api"
+	"github.com/erikgmatos/gobid/internal/mailer"
 	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/storage"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -49,15 +55,84 @@ func main() {
 	s.Cookie.HttpOnly = true
 	s.Cookie.SameSite = http.SameSiteLaxMode
 
+	imageStorage, err := storage.NewLocalStorage(
+		envString("GOBID_UPLOADS_DIR", "./uploads"),
+		envString("GOBID_UPLOADS_BASE_URL", "http://localhost:3080/uploads"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	mail, err := newMailer()
+	if err != nil {
+		panic(err)
+	}
+
+	appURL := envString("GOBID_APP_URL", "http://localhost:3080")
+	notificationServices := services.NewNotificationService(pool)
+	go notificationServices.RunAuctionEndNotifications(ctx, envDuration("GOBID_AUCTION_END_INTERVAL", services.DefaultAuctionEndInterval))
+	userServices := services.NewUserService(pool, services.UserServiceConfig{
+		Mailer:          mail,
+		AppURL:          appURL,
+		VerificationKey: verificationKey(),
+		LoginProtection: services.LoginProtection{
+			FreeAttempts:       envInt("GOBID_LOGIN_FREE_ATTEMPTS", services.DefaultLoginProtection.FreeAttempts, 0),
+			BaseDelay:          envDuration("GOBID_LOGIN_BASE_DELAY", services.DefaultLoginProtection.BaseDelay),
+			MaxDelay:           envDuration("GOBID_LOGIN_MAX_DELAY", services.DefaultLoginProtection.MaxDelay),
+			MaxAccountFailures: envInt("GOBID_LOGIN_MAX_ACCOUNT_FAILURES", services.DefaultLoginProtection.MaxAccountFailures, 1),
+			MaxIPFailures:      envInt("GOBID_LOGIN_MAX_IP_FAILURES", services.DefaultLoginProtection.MaxIPFailures, 1),
+			FailureWindow:      envDuration("GOBID_LOGIN_FAILURE_WINDOW", services.DefaultLoginProtection.FailureWindow),
+			LockoutDuration:    envDuration("GOBID_LOGIN_LOCKOUT_DURATION", services.DefaultLoginProtection.LockoutDuration),
+		},
+	})
+
+	go userServices.RunSessionPruning(ctx, envDuration("GOBID_SESSION_PRUNE_INTERVAL", services.DefaultSessionPruneInterval))
+
+	moderationServices := services.NewModerationService(pool)
+	// GOBID_ADMIN_USER_IDS promotes the listed users to admin at startup, so
+	// a fresh database has someone able to hand out the other roles.
+	if err := moderationServices.BootstrapAdmins(ctx, envUUIDs("GOBID_ADMIN_USER_IDS")); err != nil {
+		panic(err)
+	}
+
+	watchlistServices := services.NewWatchlistService(pool, mail, appURL, notificationServices)
+	go watchlistServices.RunEndingSoonAlerts(ctx, services.EndingSoonAlerts{
+		Interval: envDuration("GOBID_ENDING_SOON_INTERVAL", services.DefaultEndingSoonAlerts.Interval),
+		Window:   envDuration("GOBID_ENDING_SOON_WINDOW", services.DefaultEndingSoonAlerts.Window),
+	})
+
+	savedSearchServices := services.NewSavedSearchService(pool, mail, appURL, notificationServices)
+	go savedSearchServices.RunDigests(ctx, envDuration("GOBID_SEARCH_DIGEST_INTERVAL", services.DefaultSearchDigestInterval))
+
 	api := api.Api{
-		Router:          chi.NewMux(),
-		UserServices:    services.NewUserService(pool),
-		ProductServices: services.NewProductService(pool),
-		BidsServices:    services.NewBidsService(pool),
-		Sessions:        s,
-		WsUpgrader:      websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }},
+		Router:               chi.NewMux(),
+		UserServices:         userServices,
+		ProductServices:      services.NewProductService(pool, imageStorage, appURL, notificationServices),
+		BidsServices:         services.NewBidsService(pool),
+		CategoryServices:     services.NewCategoryService(pool),
+		AccessTokenServices:  services.NewAccessTokenService(pool),
+		ModerationServices:   moderationServices,
+		FeedbackServices:     services.NewFeedbackService(pool),
+		WatchlistServices:    watchlistServices,
+		SavedSearchServices:  savedSearchServices,
+		NotificationServices: notificationServices,
+		Sessions:             s,
+		Uploads:              imageStorage.Handler(),
+		WsUpgrader:           websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }},
 		AuctionLobby: services.AuctionLobby{
 			Rooms: make(map[uuid.UUID]*services.AuctionRoom),
+			RoomConfig: services.AuctionRoomConfig{
+				BidRateLimit: services.BidRateLimit{
+					BidsPerSecond: envFloat("GOBID_BID_RATE_PER_SECOND", services.DefaultBidRateLimit.BidsPerSecond),
+					Burst:         envInt("GOBID_BID_RATE_BURST", services.DefaultBidRateLimit.Burst, 1),
+					MaxViolations: envInt("GOBID_BID_RATE_MAX_VIOLATIONS", services.DefaultBidRateLimit.MaxViolations, 1),
+				},
+				Countdown: services.Countdown{
+					Interval:      envDuration("GOBID_COUNTDOWN_INTERVAL", services.DefaultCountdown.Interval),
+					FinalInterval: envDuration("GOBID_COUNTDOWN_FINAL_INTERVAL", services.DefaultCountdown.FinalInterval),
+					FinalWindow:   envDuration("GOBID_COUNTDOWN_FINAL_WINDOW", services.DefaultCountdown.FinalWindow),
+				},
+			},
 		},
 	}
 	api.BindRoutes()
@@ -67,3 +142,98 @@ func main() {
 		panic(err)
 	}
 }
+
+// verificationKey reads GOBID_VERIFICATION_KEY. Without it a random key is
+// used and the verification links sent before a restart stop working.
+func verificationKey() []byte {
+	if key := os.Getenv("GOBID_VERIFICATION_KEY"); key != "" {
+		return []byte(key)
+	}
+	slog.Warn("GOBID_VERIFICATION_KEY is not set, using a random key")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// newMailer picks the mailer from GOBID_MAILER: log (default), file or smtp.
+func newMailer() (mailer.Mailer, error) {
+	from := envString("GOBID_MAIL_FROM", "GoBid <no-reply@gobid.local>")
+	switch envString("GOBID_MAILER", "log") {
+	case "file":
+		return mailer.NewFileMailer(envString("GOBID_MAIL_DIR", "./mail"), from)
+	case "smtp":
+		return mailer.NewSMTPMailer(
+			os.Getenv("GOBID_SMTP_ADDR"),
+			os.Getenv("GOBID_SMTP_USER"),
+			os.Getenv("GOBID_SMTP_PASSWORD"),
+			from,
+		)
+	default:
+		return mailer.LogMailer{}, nil
+	}
+}
+
+func envString(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envInt falls back for values below min, a zero burst or failure limit
+// would reject every bid or login.
+func envInt(key string, fallback, min int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < min {
+		slog.Warn("Ignoring invalid setting", "key", key, "value", raw, "min", min, "fallback", fallback)
+		return fallback
+	}
+	return value
+}
+
+// envFloat falls back for zero and negative values, like envDuration.
+func envFloat(key string, fallback float64) float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value <= 0 {
+		slog.Warn("Ignoring invalid setting", "key", key, "value", raw, "fallback", fallback)
+		return fallback
+	}
+	return value
+}
+
+// envDuration falls back for zero and negative values too, the intervals
+// read with it would make time.NewTicker panic.
+func envDuration(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil || value <= 0 {
+		slog.Warn("Ignoring invalid setting", "key", key, "value", raw, "fallback", fallback)
+		return fallback
+	}
+	return value
+}
+
+func envUUIDs(key string) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, raw := range strings.Split(os.Getenv(key), ",") {
+		id, err := uuid.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
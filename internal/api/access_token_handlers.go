@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/user"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleCreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.CreateAccessTokenReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	slices.Sort(data.Scopes)
+	token, err := api.AccessTokenServices.CreateToken(r.Context(), userId, data.Name, slices.Compact(data.Scopes), data.ExpiresAt)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, token)
+}
+
+func (api *Api) handleListAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	tokens, err := api.AccessTokenServices.ListTokens(r.Context(), userId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"tokens": tokens})
+}
+
+func (api *Api) handleRevokeAccessToken(w http.ResponseWriter, r *http.Request) {
+	tokenId, err := uuid.Parse(chi.URLParam(r, "token_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid token id - must be a valid uuid"})
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	if err := api.AccessTokenServices.RevokeToken(r.Context(), userId, tokenId); err != nil {
+		if errors.Is(err, services.ErrAccessTokenNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "token revoked"})
+}
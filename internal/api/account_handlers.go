@@ -0,0 +1,109 @@
+package api
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/user"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleExportAccount(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "zip" {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "format must be json or zip"})
+		return
+	}
+
+	export, err := api.UserServices.ExportAccount(r.Context(), userId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	filename := fmt.Sprintf("gobid-export-%s", export.ExportedAt.Format("20060102"))
+	if format != "zip" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+		jsonutils.EncodeJson(w, r, http.StatusOK, export)
+		return
+	}
+
+	// One file per kind of data, the archive is streamed as it is written.
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".zip"))
+	w.WriteHeader(http.StatusOK)
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"products.json", export.Products},
+		{"bids.json", export.Bids},
+		{"sessions.json", export.Sessions},
+	}
+	for _, file := range files {
+		f, err := archive.Create(file.name)
+		if err != nil {
+			slog.Error("Failed to write account export", "user_id", userId, "error", err)
+			return
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			slog.Error("Failed to write account export", "user_id", userId, "error", err)
+			return
+		}
+	}
+	if err := archive.Close(); err != nil {
+		slog.Error("Failed to write account export", "user_id", userId, "error", err)
+	}
+}
+
+func (api *Api) handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.DeleteAccountReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.UserServices.DeleteAccount(r.Context(), userId, data.Password, data.Code, data.RecoveryCode); err != nil {
+		var blocked *services.AccountDeletionBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": blocked.Error(), "blockers": blocked})
+		case errors.Is(err, services.ErrInvalidCredentials):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"password": "is not correct"})
+		case errors.Is(err, services.ErrInvalidTwoFactorCode):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"code": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	// The account is gone, drop every cookie session and websocket left.
+	if err := api.revokeUserSessions(r.Context(), userId, uuid.Nil); err != nil {
+		slog.Error("Failed to revoke sessions", "user_id", userId, "error", err)
+	}
+	if err := api.Sessions.Destroy(r.Context()); err != nil {
+		slog.Error("Failed to destroy session", "user_id", userId, "error", err)
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "account deleted"})
+}
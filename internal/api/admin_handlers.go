@@ -0,0 +1,241 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/admin"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleChangeUserRole(w http.ResponseWriter, r *http.Request) {
+	actorId, userId, ok := api.moderationTarget(w, r, "user_id")
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[admin.ChangeRoleReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := api.ModerationServices.ChangeRole(r.Context(), actorId, userId, data.Role); err != nil {
+		api.encodeModerationError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "role changed", "role": data.Role})
+}
+
+func (api *Api) handleSuspendUser(w http.ResponseWriter, r *http.Request) {
+	actorId, userId, ok := api.moderationTarget(w, r, "user_id")
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[admin.ReasonReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := api.ModerationServices.SuspendUser(r.Context(), actorId, userId, data.Reason); err != nil {
+		api.encodeModerationError(w, r, err)
+		return
+	}
+	// Access tokens are rejected by AuthMiddleware, sessions and auction
+	// websockets are dropped so the user is logged out right away.
+	if err := api.revokeUserSessions(r.Context(), userId, uuid.Nil); err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "user suspended, but failed to end the sessions"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "user suspended"})
+}
+
+func (api *Api) handleUnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	actorId, userId, ok := api.moderationTarget(w, r, "user_id")
+	if !ok {
+		return
+	}
+
+	if err := api.ModerationServices.UnsuspendUser(r.Context(), actorId, userId); err != nil {
+		api.encodeModerationError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "user unsuspended"})
+}
+
+func (api *Api) handleCancelAuction(w http.ResponseWriter, r *http.Request) {
+	actorId, productId, ok := api.moderationTarget(w, r, "product_id")
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[admin.ReasonReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := api.ModerationServices.CancelAuction(r.Context(), actorId, productId, data.Reason); err != nil {
+		api.encodeModerationError(w, r, err)
+		return
+	}
+
+	api.AuctionLobby.Lock()
+	room, ok := api.AuctionLobby.Rooms[productId]
+	delete(api.AuctionLobby.Rooms, productId)
+	api.AuctionLobby.Unlock()
+	if ok {
+		room.Withdraw(data.Reason)
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "auction canceled"})
+}
+
+func (api *Api) handleVoidBid(w http.ResponseWriter, r *http.Request) {
+	actorId, bidId, ok := api.moderationTarget(w, r, "bid_id")
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[admin.ReasonReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	bid, err := api.ModerationServices.VoidBid(r.Context(), actorId, bidId, data.Reason)
+	if err != nil {
+		api.encodeModerationError(w, r, err)
+		return
+	}
+
+	// The voided bid may have been the highest one, the room has to learn
+	// about the new current price.
+	api.AuctionLobby.Lock()
+	room, ok := api.AuctionLobby.Rooms[bid.ProductID]
+	api.AuctionLobby.Unlock()
+	if ok {
+		snapshot, err := api.ProductServices.GetAuctionSnapshot(r.Context(), bid.ProductID)
+		if err == nil {
+			room.Update(snapshot)
+		}
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "bid voided"})
+}
+
+func (api *Api) handleListAuditLog(w http.ResponseWriter, r *http.Request) {
+	var targetId *uuid.UUID
+	if raw := r.URL.Query().Get("target_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid target id - must be a valid uuid"})
+			return
+		}
+		targetId = &id
+	}
+	limit, ok := adminPageLimit(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := api.ModerationServices.ListAuditLog(r.Context(), targetId, limit)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"entries": entries})
+}
+
+func (api *Api) handleListShillFlags(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = services.ShillFlagOpen
+	}
+	limit, ok := adminPageLimit(w, r)
+	if !ok {
+		return
+	}
+
+	flags, err := api.ModerationServices.ListShillFlags(r.Context(), status, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidShillFlagStatus) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"flags": flags})
+}
+
+func (api *Api) handleReviewShillFlag(w http.ResponseWriter, r *http.Request) {
+	actorId, flagId, ok := api.moderationTarget(w, r, "flag_id")
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[admin.ReviewShillFlagReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	flag, err := api.ModerationServices.ReviewShillFlag(r.Context(), actorId, flagId, data.Status)
+	if err != nil {
+		if errors.Is(err, services.ErrShillFlagNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, flag)
+}
+
+// adminPageLimit reads the limit query parameter of the admin listings.
+func adminPageLimit(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	limit := 50
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 200 {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "limit must be between 1 and 200"})
+			return 0, false
+		}
+		limit = n
+	}
+	return int32(limit), true
+}
+
+// moderationTarget returns the acting user and the id in the url parameter
+// param, writing the error response when either is missing.
+func (api *Api) moderationTarget(w http.ResponseWriter, r *http.Request, param string) (uuid.UUID, uuid.UUID, bool) {
+	actorId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+	targetId, err := uuid.Parse(chi.URLParam(r, param))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid id - must be a valid uuid"})
+		return uuid.UUID{}, uuid.UUID{}, false
+	}
+	return actorId, targetId, true
+}
+
+func (api *Api) encodeModerationError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no user with given id"})
+	case errors.Is(err, services.ErrProductNotFond):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+	case errors.Is(err, services.ErrBidNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrCannotModerateSelf), errors.Is(err, services.ErrCannotModerateRole):
+		jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrUserSuspended), errors.Is(err, services.ErrUserNotSuspended),
+		errors.Is(err, services.ErrProductWithdrawn), errors.Is(err, services.ErrAuctionEnded):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+	default:
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+	}
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/alexedwards/scs/v2"
 	"github.com/erikgmatos/gobid/internal/services"
 	"github.com/go-chi/chi/v5"
@@ -8,11 +10,21 @@ import (
 )
 
 type Api struct {
-	Router          *chi.Mux
-	UserServices    services.UserService
-	ProductServices services.ProductService
-	Sessions        *scs.SessionManager
-	WsUpgrader      websocket.Upgrader
-	AuctionLobby    services.AuctionLobby
-	BidsServices    services.BidsService
+	Router              *chi.Mux
+	UserServices        services.UserService
+	ProductServices     services.ProductService
+	Sessions            *scs.SessionManager
+	WsUpgrader          websocket.Upgrader
+	AuctionLobby        services.AuctionLobby
+	BidsServices        services.BidsService
+	CategoryServices    services.CategoryService
+	AccessTokenServices services.AccessTokenService
+	ModerationServices  services.ModerationService
+	FeedbackServices    services.FeedbackService
+	WatchlistServices   services.WatchlistService
+	SavedSearchServices services.SavedSearchService
+	// NotificationServices is shared with the services producing
+	// notifications, so their notifications reach the open streams.
+	NotificationServices services.NotificationService
+	Uploads              http.Handler
 }
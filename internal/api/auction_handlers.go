@@ -18,21 +18,59 @@ func (api *Api) handleSubscribeUserToAuction(w http.ResponseWriter, r *http.Requ
 		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"message": "invalid product id - must be a valid uuid"})
 		return
 	}
-	_, err = api.ProductServices.GetProductById(r.Context(), productId)
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+		return
+	}
+
+	// Invite only auctions are hidden from the users who were not invited,
+	// before anything else about them is reported.
+	if _, err := api.ProductServices.GetVisibleProduct(r.Context(), productId, &userId); err != nil {
+		if errors.Is(err, services.ErrProductNotFond) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"message": "no product with given id"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+		return
+	}
+
+	snapshot, err := api.ProductServices.GetAuctionSnapshot(r.Context(), productId)
 	if err != nil {
 		if errors.Is(err, services.ErrProductNotFond) {
 			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"message": "no product with given id"})
 			return
 		}
+		if errors.Is(err, services.ErrProductWithdrawn) {
+			jsonutils.EncodeJson(w, r, http.StatusGone, map[string]any{"message": err.Error()})
+			return
+		}
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
 		return
 	}
 
-	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
-	if !ok {
+	if err := api.ProductServices.CheckEligibility(r.Context(), productId, userId); err != nil {
+		var notEligible *services.NotEligibleError
+		if errors.As(err, &notEligible) {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"message": notEligible.Reason, "code": services.ErrCodeNotEligible})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+		return
+	}
+
+	blocked, err := api.UserServices.IsBlockedBySeller(r.Context(), snapshot.SellerId, userId)
+	if err != nil {
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
 		return
 	}
+	if blocked {
+		jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{
+			"message": services.ErrBlockedBySeller.Error(),
+			"code":    services.ErrCodeBlockedBySeller,
+		})
+		return
+	}
 
 	api.AuctionLobby.Lock()
 	room, ok := api.AuctionLobby.Rooms[productId]
@@ -49,7 +87,10 @@ func (api *Api) handleSubscribeUserToAuction(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	client := services.NewClient(room, conn, userId)
+	// Token clients have no session, uuid.Nil is never revoked on its own.
+	sessionId, _ := api.currentSessionId(r)
+	client := services.NewClient(room, conn, userId, sessionId)
+	client.Send <- services.Message{Kind: services.RoomSnapshot, Snapshot: &snapshot}
 
 	room.Register <- client
 
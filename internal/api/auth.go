@@ -1,23 +1,190 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/google/uuid"
 	"github.com/gorilla/csrf"
 )
 
+type contextKey string
+
+// tokenIdentityKey holds the services.TokenIdentity of requests authenticated
+// with a personal access token instead of the session cookie.
+const tokenIdentityKey contextKey = "tokenIdentity"
+
+// authzKey holds the services.Authz of the authenticated user, loaded once by
+// AuthMiddleware for the permission checks down the chain.
+const authzKey contextKey = "authz"
+
 func (api *Api) HandleGetCsrfToken(w http.ResponseWriter, r *http.Request) {
 	token := csrf.Token(r)
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"csrf_token": token})
 }
 
+// AuthMiddleware accepts the session cookie or an "Authorization: Bearer"
+// personal access token, and rejects suspended users.
 func (api *Api) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !api.Sessions.Exists(r.Context(), "AuthenticatedUserId") {
+		ctx := r.Context()
+		if header := r.Header.Get("Authorization"); header != "" {
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]string{"error": "authorization header must be a bearer token"})
+				return
+			}
+			identity, err := api.AccessTokenServices.Authenticate(ctx, strings.TrimSpace(token))
+			if err != nil {
+				if errors.Is(err, services.ErrInvalidAccessToken) {
+					jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+					return
+				}
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": "unexpected error, try again later"})
+				return
+			}
+			ctx = context.WithValue(ctx, tokenIdentityKey, identity)
+		}
+
+		userId, ok := api.authenticatedUserId(r.WithContext(ctx))
+		if !ok {
 			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]string{"error": "must be logged in"})
 			return
 		}
+		if _, isToken := ctx.Value(tokenIdentityKey).(services.TokenIdentity); !isToken {
+			active, err := api.checkSession(r, userId)
+			if err != nil {
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": "unexpected error, try again later"})
+				return
+			}
+			if !active {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]string{"error": "session was revoked, log in again"})
+				return
+			}
+		}
+
+		authz, err := api.UserServices.GetAuthz(ctx, userId)
+		if err != nil {
+			if errors.Is(err, services.ErrUserNotFound) {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]string{"error": "must be logged in"})
+				return
+			}
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{"error": "unexpected error, try again later"})
+			return
+		}
+		if authz.Suspended {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]string{"error": services.ErrAccountSuspended.Error(), "code": services.ErrCodeAccountSuspended})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, authzKey, authz)))
+	})
+}
+
+// OptionalAuthMiddleware runs AuthMiddleware on requests that carry a
+// session or a bearer token and lets anonymous ones through, for public
+// routes that show more to logged in users.
+func (api *Api) OptionalAuthMiddleware(next http.Handler) http.Handler {
+	authenticated := api.AuthMiddleware(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" && !api.Sessions.Exists(r.Context(), "AuthenticatedUserId") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authenticated.ServeHTTP(w, r)
+	})
+}
+
+// checkSession reports whether the session of the request was not revoked,
+// revoked ones are destroyed. Sessions from before session tracking are
+// registered on their first use.
+func (api *Api) checkSession(r *http.Request, userId uuid.UUID) (bool, error) {
+	sessionId, ok := api.Sessions.Get(r.Context(), "SessionId").(uuid.UUID)
+	if !ok {
+		sessionId, err := api.UserServices.StartSession(r.Context(), userId, api.sessionToken(r.Context()), clientIP(r), r.UserAgent())
+		if err != nil {
+			return false, err
+		}
+		api.Sessions.Put(r.Context(), "SessionId", sessionId)
+		return true, nil
+	}
+
+	active, err := api.UserServices.TouchSession(r.Context(), userId, sessionId, api.sessionToken(r.Context()), clientIP(r), r.UserAgent())
+	if err != nil {
+		return false, err
+	}
+	if !active {
+		return false, api.Sessions.Destroy(r.Context())
+	}
+	return true, nil
+}
+
+// RequirePermission only lets users whose role grants perm through, it must
+// run after AuthMiddleware.
+func (api *Api) RequirePermission(perm services.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authz, ok := r.Context().Value(authzKey).(services.Authz)
+			if !ok {
+				jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]string{"error": "must be logged in"})
+				return
+			}
+			if !authz.Can(perm) {
+				jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]string{"error": services.ErrPermissionDenied.Error(), "code": services.ErrCodePermissionDenied})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScope only lets personal access tokens with scope through, session
+// users are not limited.
+func (api *Api) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := r.Context().Value(tokenIdentityKey).(services.TokenIdentity)
+			if ok && !slices.Contains(identity.Scopes, scope) {
+				jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]string{"error": "token is missing the " + scope + " scope"})
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession rejects personal access tokens, used on account settings a
+// leaked token must not be able to change.
+func (api *Api) RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(tokenIdentityKey).(services.TokenIdentity); ok {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]string{"error": "this endpoint cannot be used with an access token"})
+			return
+		}
 		next.ServeHTTP(w, r)
 	})
 }
+
+// authenticatedUserId is the user behind the request, whether it came with
+// the session cookie or with a personal access token.
+func (api *Api) authenticatedUserId(r *http.Request) (uuid.UUID, bool) {
+	if identity, ok := r.Context().Value(tokenIdentityKey).(services.TokenIdentity); ok {
+		return identity.UserId, true
+	}
+	userId, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	return userId, ok
+}
+
+// viewerId is authenticatedUserId for routes behind OptionalAuthMiddleware,
+// nil when the request is anonymous.
+func (api *Api) viewerId(r *http.Request) *uuid.UUID {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		return nil
+	}
+	return &userId
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/bid"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleListBids(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id - must be a valid uuid"})
+		return
+	}
+	req, problems := bid.NewBidHistoryReq(r.URL.Query())
+	if len(problems) > 0 {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if _, err := api.ProductServices.GetVisibleProduct(r.Context(), productId, api.viewerId(r)); err != nil {
+		if errors.Is(err, services.ErrProductNotFond) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	page, err := api.BidsServices.GetBidHistory(r.Context(), productId, req.Cursor, req.Limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCursor):
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid cursor"})
+		case errors.Is(err, services.ErrProductNotFond):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+		case errors.Is(err, services.ErrProductWithdrawn):
+			jsonutils.EncodeJson(w, r, http.StatusGone, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, page)
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/category"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := api.CategoryServices.ListCategories(r.Context())
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"categories": categories})
+}
+
+func (api *Api) handleCreateCategory(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[category.CategoryReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	created, err := api.CategoryServices.CreateCategory(r.Context(), data.ParentID, data.Name, data.Slug)
+	if err != nil {
+		api.encodeCategoryError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, created)
+}
+
+func (api *Api) handleUpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "category_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid category id - must be a valid uuid"})
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[category.CategoryReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	updated, err := api.CategoryServices.UpdateCategory(r.Context(), id, data.ParentID, data.Name, data.Slug)
+	if err != nil {
+		api.encodeCategoryError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, updated)
+}
+
+func (api *Api) handleDeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "category_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid category id - must be a valid uuid"})
+		return
+	}
+
+	if err := api.CategoryServices.DeleteCategory(r.Context(), id); err != nil {
+		api.encodeCategoryError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "category deleted"})
+}
+
+func (api *Api) encodeCategoryError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrCategoryNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrDuplicatedCategory),
+		errors.Is(err, services.ErrCategoryCycle),
+		errors.Is(err, services.ErrCategoryInUse):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+	default:
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+	}
+}
+
+func (api *Api) handleListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := api.CategoryServices.ListTags(r.Context())
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"tags": tags})
+}
+
+func (api *Api) handleCreateTag(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[category.TagReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	id, err := api.CategoryServices.CreateTag(r.Context(), data.Name)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{"tag_id": id})
+}
+
+func (api *Api) handleDeleteTag(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "tag_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid tag id - must be a valid uuid"})
+		return
+	}
+
+	if err := api.CategoryServices.DeleteTag(r.Context(), id); err != nil {
+		if errors.Is(err, services.ErrTagNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "tag deleted"})
+}
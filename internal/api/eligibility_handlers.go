@@ -0,0 +1,122 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/product"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleGetEligibility(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id - must be a valid uuid"})
+		return
+	}
+
+	policy, err := api.ProductServices.GetEligibilityPolicy(r.Context(), productId, api.viewerId(r))
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFond) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, policy)
+}
+
+func (api *Api) handleSetEligibility(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[product.EligibilityPolicyReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	policy, err := api.ProductServices.SetEligibilityPolicy(r.Context(), productId, services.EligibilityPolicy{
+		RequireVerifiedEmail: data.RequireVerifiedEmail,
+		MinAccountAgeDays:    data.MinAccountAgeDays,
+		MinFeedbackScore:     data.MinFeedbackScore,
+		InviteOnly:           data.InviteOnly,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFond):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+		case errors.Is(err, services.ErrProductHasBids):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": "bidder rules cannot change after the first bid"})
+		case errors.Is(err, services.ErrProductWithdrawn), errors.Is(err, services.ErrAuctionEnded):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, policy)
+}
+
+func (api *Api) handleListInvitations(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+
+	invitations, err := api.ProductServices.ListInvitations(r.Context(), productId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"invitations": invitations})
+}
+
+func (api *Api) handleInviteBidder(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[product.InviteBidderReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := api.ProductServices.InviteBidder(r.Context(), productId, data.UserID); err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no user with given id"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "user invited"})
+}
+
+func (api *Api) handleUninviteBidder(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	userId, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid user id - must be a valid uuid"})
+		return
+	}
+
+	if err := api.ProductServices.UninviteBidder(r.Context(), productId, userId); err != nil {
+		if errors.Is(err, services.ErrInvitationNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "invitation removed"})
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/feedback"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleLeaveFeedback(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id - must be a valid uuid"})
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[feedback.LeaveFeedbackReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	entry, err := api.FeedbackServices.LeaveFeedback(r.Context(), productId, userId, *data.Score, data.Comment)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFond):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+		case errors.Is(err, services.ErrNotPartOfSale):
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrSaleNotSettled), errors.Is(err, services.ErrFeedbackAlreadyLeft):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, entry)
+}
+
+func (api *Api) handleListProductFeedback(w http.ResponseWriter, r *http.Request) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id - must be a valid uuid"})
+		return
+	}
+	if _, err := api.ProductServices.GetVisibleProduct(r.Context(), productId, api.viewerId(r)); err != nil {
+		if errors.Is(err, services.ErrProductNotFond) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	entries, err := api.FeedbackServices.ListProductFeedback(r.Context(), productId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"feedback": entries})
+}
+
+func (api *Api) handleListUserFeedback(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid user id - must be a valid uuid"})
+		return
+	}
+	req, problems := feedback.NewListFeedbackReq(r.URL.Query())
+	if len(problems) > 0 {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	page, err := api.FeedbackServices.ListUserFeedback(r.Context(), userId, api.viewerId(r), services.FeedbackRole(req.Role), req.Cursor, req.Limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCursor):
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid cursor"})
+		case errors.Is(err, services.ErrUserNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no user with given id"})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, page)
+}
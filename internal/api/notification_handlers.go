@@ -0,0 +1,106 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/notification"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleListNotifications(w http.ResponseWriter, r *http.Request) {
+	req, problems := notification.NewListNotificationsReq(r.URL.Query())
+	if len(problems) > 0 {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	page, err := api.NotificationServices.ListNotifications(r.Context(), userId, req.UnreadOnly, req.Cursor, req.Limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid cursor"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, page)
+}
+
+func (api *Api) handleCountUnreadNotifications(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	unread, err := api.NotificationServices.CountUnread(r.Context(), userId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"unread_count": unread})
+}
+
+func (api *Api) handleMarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	notificationId, err := uuid.Parse(chi.URLParam(r, "notification_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid notification id - must be a valid uuid"})
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.NotificationServices.MarkRead(r.Context(), userId, notificationId); err != nil {
+		if errors.Is(err, services.ErrNotificationNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "notification marked as read"})
+}
+
+func (api *Api) handleMarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	marked, err := api.NotificationServices.MarkAllRead(r.Context(), userId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"marked_read": marked})
+}
+
+func (api *Api) handleStreamNotifications(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "unexpected error, try again later"})
+		return
+	}
+
+	conn, err := api.WsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"message": "could not upgrade connection toa websocket protocol"})
+		return
+	}
+
+	sessionId, _ := api.currentSessionId(r)
+	go api.NotificationServices.Stream(conn, userId, sessionId)
+}
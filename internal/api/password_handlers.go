@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/user"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.ChangePasswordReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.UserServices.ChangePassword(r.Context(), userId, data.CurrentPassword, data.NewPassword); err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"current_password": "is not correct"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	// Keep this device logged in with a fresh token and log out the others.
+	if err := api.Sessions.RenewToken(r.Context()); err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+	current, _ := api.currentSessionId(r)
+	if err := api.revokeUserSessions(r.Context(), userId, current); err != nil {
+		slog.Error("Failed to revoke sessions", "user_id", userId, "error", err)
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "password changed"})
+}
+
+func (api *Api) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.ForgotPasswordReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if err := api.UserServices.RequestPasswordReset(r.Context(), data.Email, clientIP(r)); err != nil {
+		if errors.Is(err, services.ErrTooManyResetRequests) {
+			jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{"error": err.Error()})
+			return
+		}
+		slog.Error("Failed to request password reset", "error", err)
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusAccepted, map[string]any{
+		"message": "if the email belongs to an account a reset link was sent to it",
+	})
+}
+
+func (api *Api) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.ResetPasswordReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, err := api.UserServices.ResetPassword(r.Context(), data.Token, data.NewPassword)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.revokeUserSessions(r.Context(), userId, uuid.Nil); err != nil {
+		slog.Error("Failed to revoke sessions", "user_id", userId, "error", err)
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "password changed but sessions could not be revoked"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "password reset, log in with the new password"})
+}
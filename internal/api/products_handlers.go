@@ -2,14 +2,19 @@ package api
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/erikgmatos/gobid/internal/jsonutils"
 	"github.com/erikgmatos/gobid/internal/services"
 	"github.com/erikgmatos/gobid/internal/usecase/product"
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
+const maxUploadMemory = 10 << 20
+
 func (api *Api) handleCreateProduct(w http.ResponseWriter, r *http.Request) {
 	data, problems, err := jsonutils.DecodeValidJson[product.CreateProductReq](r)
 	if err != nil {
@@ -17,38 +22,345 @@ func (api *Api) handleCreateProduct(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID, ok := api.Sessions.Get(r.Context(), "AuthenticatedUserId").(uuid.UUID)
+	userID, ok := api.authenticatedUserId(r)
 	if !ok {
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{
 			"error": "unexpected error try again later",
 		})
 		return
 	}
+	if err := api.UserServices.RequireVerifiedEmail(r.Context(), userID); err != nil {
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]string{
+				"error": err.Error(),
+				"code":  services.ErrCodeEmailNotVerified,
+			})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{
+			"error": "unexpected error try again later",
+		})
+		return
+	}
+	visibility := services.Visibility(data.Visibility)
+	if visibility == "" {
+		visibility = services.VisibilityPublic
+	}
 	productId, err := api.ProductServices.CreateProduct(
 		r.Context(),
 		userID,
 		data.ProductName,
 		data.Description,
 		data.BasePrice,
-		data.AuctionEnd)
+		data.AuctionEnd,
+		data.CategoryID,
+		data.Tags,
+		visibility)
 	if err != nil {
+		if errors.Is(err, services.ErrCategoryNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{
+				"category_id": "category does not exist",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrPermissionDenied) || errors.Is(err, services.ErrAccountSuspended) {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]string{
+				"error": err.Error(),
+			})
+			return
+		}
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]string{
 			"error": "failed to create product auction try again later",
 		})
 		return
 	}
 
-	ctx, _ := context.WithDeadline(context.Background(), data.AuctionEnd)
-
-	auctionRomm := services.NewAuctionRoom(ctx, productId, api.BidsServices)
+	auctionRomm := services.NewAuctionRoom(context.Background(), productId, data.AuctionEnd, api.BidsServices, api.NotificationServices, api.AuctionLobby.RoomConfig)
 
 	go auctionRomm.Run()
 	api.AuctionLobby.Lock()
 	api.AuctionLobby.Rooms[productId] = auctionRomm
 	api.AuctionLobby.Unlock()
 
+	go api.SavedSearchServices.MatchProduct(productId)
+
 	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{
 		"message":    "auction ha started with success",
 		"product_id": productId,
 	})
 }
+
+func (api *Api) handleUpdateProduct(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[product.UpdateProductReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	updated, err := api.ProductServices.EditProduct(r.Context(), productId, services.ProductChanges{
+		ProductName: data.ProductName,
+		Description: data.Description,
+		BasePrice:   data.BasePrice,
+		AuctionEnd:  data.AuctionEnd,
+		CategoryId:  data.CategoryID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFond):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+		case errors.Is(err, services.ErrCategoryNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"category_id": "category does not exist"})
+		case errors.Is(err, services.ErrProductHasBids), errors.Is(err, services.ErrDescriptionRewrite),
+			errors.Is(err, services.ErrProductWithdrawn), errors.Is(err, services.ErrAuctionEnded):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	api.AuctionLobby.Lock()
+	room, ok := api.AuctionLobby.Rooms[productId]
+	api.AuctionLobby.Unlock()
+	if ok {
+		snapshot, err := api.ProductServices.GetAuctionSnapshot(r.Context(), productId)
+		if err == nil {
+			room.Update(snapshot)
+		}
+	}
+	// The edit can make the auction match searches it did not match before.
+	go api.SavedSearchServices.MatchProduct(productId)
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, updated)
+}
+
+func (api *Api) handleWithdrawProduct(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	// The body is optional, a withdrawal without bids needs no reason.
+	data, problems, err := jsonutils.DecodeValidJson[product.WithdrawProductReq](r)
+	if err != nil && !errors.Is(err, io.EOF) {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	if _, err := api.ProductServices.WithdrawProduct(r.Context(), productId, data.Reason); err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFond):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+		case errors.Is(err, services.ErrWithdrawalReasonMissing):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"reason": err.Error()})
+		case errors.Is(err, services.ErrProductWithdrawn), errors.Is(err, services.ErrAuctionEnded):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+
+	api.AuctionLobby.Lock()
+	room, ok := api.AuctionLobby.Rooms[productId]
+	delete(api.AuctionLobby.Rooms, productId)
+	api.AuctionLobby.Unlock()
+	if ok {
+		reason := data.Reason
+		if reason == "" {
+			reason = "the seller withdrew this auction"
+		}
+		room.Withdraw(reason)
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "auction withdrawn"})
+}
+
+func (api *Api) handleListProducts(w http.ResponseWriter, r *http.Request) {
+	req, problems := product.NewListProductsReq(r.URL.Query())
+	if len(problems) > 0 {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	page, err := api.ProductServices.ListProducts(r.Context(), services.ProductFilter{
+		Status:       services.ProductStatus(req.Status),
+		SellerId:     req.SellerID,
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		EndingAfter:  req.EndingAfter,
+		EndingBefore: req.EndingBefore,
+		CategoryId:   req.CategoryID,
+		ViewerId:     api.viewerId(r),
+	}, services.ProductSort(req.Sort), req.Cursor, req.Limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid cursor"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, page)
+}
+
+func (api *Api) handleSearchProducts(w http.ResponseWriter, r *http.Request) {
+	req, problems := product.NewSearchProductsReq(r.URL.Query())
+	if len(problems) > 0 {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	page, err := api.ProductServices.SearchProducts(r.Context(), req.Query, services.ProductFilter{
+		Status:       services.ProductStatus(req.Status),
+		SellerId:     req.SellerID,
+		MinPrice:     req.MinPrice,
+		MaxPrice:     req.MaxPrice,
+		EndingAfter:  req.EndingAfter,
+		EndingBefore: req.EndingBefore,
+		CategoryId:   req.CategoryID,
+		ViewerId:     api.viewerId(r),
+	}, req.Cursor, req.Limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid cursor"})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusOK, page)
+}
+
+func (api *Api) handleUploadProductImages(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxImagesPerProduct*services.MaxImageSize+(1<<20))
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{"error": "upload is too large or is not a valid multipart form"})
+		return
+	}
+	files := r.MultipartForm.File["images"]
+	if len(files) == 0 {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"images": "at least one image must be sent"})
+		return
+	}
+
+	images := make([]services.ProductImage, 0, len(files))
+	for _, header := range files {
+		if header.Size > services.MaxImageSize {
+			jsonutils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{"error": services.ErrImageTooLarge.Error(), "file": header.Filename, "images": images})
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "could not read uploaded file", "file": header.Filename, "images": images})
+			return
+		}
+		image, err := api.ProductServices.AddProductImage(r.Context(), productId, file)
+		file.Close()
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrImageTooLarge):
+				jsonutils.EncodeJson(w, r, http.StatusRequestEntityTooLarge, map[string]any{"error": err.Error(), "file": header.Filename, "images": images})
+			case errors.Is(err, services.ErrUnsupportedImage):
+				jsonutils.EncodeJson(w, r, http.StatusUnsupportedMediaType, map[string]any{"error": err.Error(), "file": header.Filename, "images": images})
+			case errors.Is(err, services.ErrTooManyImages),
+				errors.Is(err, services.ErrProductWithdrawn), errors.Is(err, services.ErrAuctionEnded):
+				jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error(), "file": header.Filename, "images": images})
+			case errors.Is(err, services.ErrProductNotFond):
+				jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id", "images": images})
+			default:
+				jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "failed to store image, try again later", "images": images})
+			}
+			return
+		}
+		images = append(images, image)
+	}
+
+	jsonutils.EncodeJson(w, r, http.StatusCreated, map[string]any{"images": images})
+}
+
+func (api *Api) handleDeleteProductImage(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	imageId, err := uuid.Parse(chi.URLParam(r, "image_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid image id - must be a valid uuid"})
+		return
+	}
+
+	if err := api.ProductServices.DeleteProductImage(r.Context(), productId, imageId); err != nil {
+		if errors.Is(err, services.ErrProductImageNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "image deleted"})
+}
+
+// sellerProductId reads the product id from the url and makes sure the logged
+// in user is the seller of that product. It writes the error response itself.
+func (api *Api) sellerProductId(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	productId, err := uuid.Parse(chi.URLParam(r, "product_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid product id - must be a valid uuid"})
+		return uuid.UUID{}, false
+	}
+
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return uuid.UUID{}, false
+	}
+
+	product, err := api.ProductServices.GetProductById(r.Context(), productId)
+	if err != nil {
+		if errors.Is(err, services.ErrProductNotFond) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+			return uuid.UUID{}, false
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return uuid.UUID{}, false
+	}
+	if product.SellerID != userId {
+		jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": "only the seller can change this product"})
+		return uuid.UUID{}, false
+	}
+	return productId, true
+}
+
+func (api *Api) handleSettleAuction(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.ProductServices.SettleAuction(r.Context(), productId); err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFond):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+		case errors.Is(err, services.ErrAuctionNotEnded),
+			errors.Is(err, services.ErrNoWinningBid),
+			errors.Is(err, services.ErrAlreadySettled),
+			errors.Is(err, services.ErrProductWithdrawn):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "sale settled, buyer and seller can now leave feedback"})
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/user"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleGetMe(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	profile, err := api.UserServices.GetProfile(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, profile)
+}
+
+func (api *Api) handleUpdateMe(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.UpdateProfileReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	profile, err := api.UserServices.UpdateProfile(r.Context(), userId, data.UserName, data.Bio)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDuplicatedUsername):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"user_name": err.Error()})
+		case errors.Is(err, services.ErrUserNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, profile)
+}
+
+func (api *Api) handleGetUserProfile(w http.ResponseWriter, r *http.Request) {
+	userId, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid user id - must be a valid uuid"})
+		return
+	}
+
+	profile, err := api.UserServices.GetPublicProfile(r.Context(), userId)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, profile)
+}
+
+func (api *Api) handleBecomeSeller(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.UserServices.BecomeSeller(r.Context(), userId); err != nil {
+		if errors.Is(err, services.ErrEmailNotVerified) {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error(), "code": services.ErrCodeEmailNotVerified})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "you can now sell on gobid"})
+}
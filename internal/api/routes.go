@@ -1,6 +1,9 @@
 package api
 
 import (
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/services"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -13,22 +16,142 @@ func (api *Api) BindRoutes() {
 	// )
 	// api.Router.Use(csrfMiddleware)
 
+	if api.Uploads != nil {
+		api.Router.Handle("/uploads/*", http.StripPrefix("/uploads/", api.Uploads))
+	}
+
 	api.Router.Route("/api", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
 			// r.Get("/csrftoken", api.HandleGetCsrfToken)
 			r.Route("/users", func(r chi.Router) {
 				r.Post("/signup", api.handleSignupUser)
 				r.Post("/login", api.handleLoginUser)
+				r.Post("/login/2fa", api.handleLoginTwoFactor)
+				r.Post("/password/forgot", api.handleForgotPassword)
+				r.Post("/password/reset", api.handleResetPassword)
+				r.Get("/verify-email", api.handleVerifyEmail)
 				r.Group(func(r chi.Router) {
 					r.Use(api.AuthMiddleware)
-					r.Post("/logout", api.handleLogoutUser)
+					r.With(api.RequireScope(services.ScopeRead)).Get("/me", api.handleGetMe)
+					r.With(api.RequireScope(services.ScopeRead)).Get("/me/watchlist", api.handleGetWatchlist)
+					r.Route("/me/saved-searches", func(r chi.Router) {
+						r.With(api.RequireScope(services.ScopeRead)).Get("/", api.handleListSavedSearches)
+						r.Group(func(r chi.Router) {
+							r.Use(api.RequireScope(services.ScopeBid))
+							r.Post("/", api.handleCreateSavedSearch)
+							r.Put("/{search_id}", api.handleUpdateSavedSearch)
+							r.Delete("/{search_id}", api.handleDeleteSavedSearch)
+						})
+					})
+					r.Route("/me/blocks", func(r chi.Router) {
+						r.Use(api.RequireScope(services.ScopeSell))
+						r.Get("/", api.handleListBlockedUsers)
+						r.Post("/", api.handleBlockUser)
+						r.Delete("/{user_id}", api.handleUnblockUser)
+					})
+					r.Group(func(r chi.Router) {
+						r.Use(api.RequireSession)
+						r.Post("/logout", api.handleLogoutUser)
+						r.Patch("/me", api.handleUpdateMe)
+						r.Put("/me/password", api.handleChangePassword)
+						r.Post("/me/verification", api.handleResendVerification)
+						r.Post("/me/seller", api.handleBecomeSeller)
+						r.Get("/me/export", api.handleExportAccount)
+						r.Delete("/me", api.handleDeleteAccount)
+						r.Route("/me/2fa", func(r chi.Router) {
+							r.Get("/", api.handleGetTwoFactor)
+							r.Post("/", api.handleStartTwoFactor)
+							r.Post("/confirm", api.handleConfirmTwoFactor)
+							r.Post("/recovery-codes", api.handleRegenerateRecoveryCodes)
+							r.Put("/bid-threshold", api.handleSetBidConfirmationThreshold)
+							r.Delete("/", api.handleDisableTwoFactor)
+						})
+						r.Route("/me/tokens", func(r chi.Router) {
+							r.Get("/", api.handleListAccessTokens)
+							r.Post("/", api.handleCreateAccessToken)
+							r.Delete("/{token_id}", api.handleRevokeAccessToken)
+						})
+						r.Route("/me/sessions", func(r chi.Router) {
+							r.Get("/", api.handleListSessions)
+							r.Delete("/", api.handleRevokeOtherSessions)
+							r.Delete("/{session_id}", api.handleRevokeSession)
+						})
+					})
 				})
+				r.Get("/{user_id}", api.handleGetUserProfile)
+				r.With(api.OptionalAuthMiddleware).Get("/{user_id}/feedback", api.handleListUserFeedback)
 			})
 			r.Route("/products", func(r chi.Router) {
+				r.Group(func(r chi.Router) {
+					r.Use(api.OptionalAuthMiddleware)
+					r.Get("/", api.handleListProducts)
+					r.Get("/search", api.handleSearchProducts)
+					r.Get("/{product_id}/bids", api.handleListBids)
+					r.Get("/{product_id}/eligibility", api.handleGetEligibility)
+					r.Get("/{product_id}/feedback", api.handleListProductFeedback)
+				})
 				r.Group(func(r chi.Router) {
 					r.Use(api.AuthMiddleware)
-					r.Post("/", api.handleCreateProduct)
-					r.Get("/ws/subscribe/{product_id}", api.handleSubscribeUserToAuction)
+					r.With(api.RequireScope(services.ScopeBid)).Get("/ws/subscribe/{product_id}", api.handleSubscribeUserToAuction)
+					r.With(api.RequireScope(services.ScopeBid)).Post("/invite-links/redeem", api.handleRedeemInviteLink)
+					r.With(api.RequireSession).Post("/{product_id}/feedback", api.handleLeaveFeedback)
+					r.With(api.RequireScope(services.ScopeBid)).Post("/{product_id}/watch", api.handleWatchProduct)
+					r.With(api.RequireScope(services.ScopeBid)).Delete("/{product_id}/watch", api.handleUnwatchProduct)
+					r.Group(func(r chi.Router) {
+						r.Use(api.RequireScope(services.ScopeSell), api.RequirePermission(services.PermSell))
+						r.Post("/", api.handleCreateProduct)
+						r.Patch("/{product_id}", api.handleUpdateProduct)
+						r.Delete("/{product_id}", api.handleWithdrawProduct)
+						r.Post("/{product_id}/images", api.handleUploadProductImages)
+						r.Delete("/{product_id}/images/{image_id}", api.handleDeleteProductImage)
+						r.Put("/{product_id}/eligibility", api.handleSetEligibility)
+						r.Get("/{product_id}/invitations", api.handleListInvitations)
+						r.Post("/{product_id}/invitations", api.handleInviteBidder)
+						r.Delete("/{product_id}/invitations/{user_id}", api.handleUninviteBidder)
+						r.Put("/{product_id}/visibility", api.handleSetVisibility)
+						r.Get("/{product_id}/invite-links", api.handleListInviteLinks)
+						r.Post("/{product_id}/invite-links", api.handleCreateInviteLink)
+						r.Delete("/{product_id}/invite-links/{link_id}", api.handleRevokeInviteLink)
+						r.Post("/{product_id}/settle", api.handleSettleAuction)
+						r.Get("/{product_id}/watchers", api.handleCountWatchers)
+					})
+				})
+			})
+			r.Route("/notifications", func(r chi.Router) {
+				r.Use(api.AuthMiddleware)
+				r.With(api.RequireScope(services.ScopeRead)).Get("/", api.handleListNotifications)
+				r.With(api.RequireScope(services.ScopeRead)).Get("/unread-count", api.handleCountUnreadNotifications)
+				r.With(api.RequireScope(services.ScopeRead)).Get("/ws", api.handleStreamNotifications)
+				r.With(api.RequireScope(services.ScopeBid)).Post("/read", api.handleMarkAllNotificationsRead)
+				r.With(api.RequireScope(services.ScopeBid)).Post("/{notification_id}/read", api.handleMarkNotificationRead)
+			})
+			r.Get("/categories", api.handleListCategories)
+			r.Get("/tags", api.handleListTags)
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(api.AuthMiddleware, api.RequireSession)
+				r.Route("/categories", func(r chi.Router) {
+					r.Use(api.RequirePermission(services.PermManageCatalog))
+					r.Post("/", api.handleCreateCategory)
+					r.Put("/{category_id}", api.handleUpdateCategory)
+					r.Delete("/{category_id}", api.handleDeleteCategory)
+				})
+				r.Route("/tags", func(r chi.Router) {
+					r.Use(api.RequirePermission(services.PermManageCatalog))
+					r.Post("/", api.handleCreateTag)
+					r.Delete("/{tag_id}", api.handleDeleteTag)
+				})
+				r.Route("/users/{user_id}", func(r chi.Router) {
+					r.With(api.RequirePermission(services.PermManageRoles)).Put("/role", api.handleChangeUserRole)
+					r.With(api.RequirePermission(services.PermSuspendUsers)).Post("/suspend", api.handleSuspendUser)
+					r.With(api.RequirePermission(services.PermSuspendUsers)).Post("/unsuspend", api.handleUnsuspendUser)
+				})
+				r.With(api.RequirePermission(services.PermCancelAuctions)).Post("/products/{product_id}/cancel", api.handleCancelAuction)
+				r.With(api.RequirePermission(services.PermVoidBids)).Post("/bids/{bid_id}/void", api.handleVoidBid)
+				r.With(api.RequirePermission(services.PermViewAuditLog)).Get("/audit-log", api.handleListAuditLog)
+				r.Route("/shill-flags", func(r chi.Router) {
+					r.Use(api.RequirePermission(services.PermReviewShills))
+					r.Get("/", api.handleListShillFlags)
+					r.Post("/{flag_id}/review", api.handleReviewShillFlag)
 				})
 			})
 		})
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/user"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleListSavedSearches(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	searches, err := api.SavedSearchServices.ListSavedSearches(r.Context(), userId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"saved_searches": searches})
+}
+
+func (api *Api) handleCreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.CreateSavedSearchReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	frequency := services.SearchInstant
+	if data.Frequency != "" {
+		frequency = services.SearchFrequency(data.Frequency)
+	}
+	search, err := api.SavedSearchServices.CreateSavedSearch(r.Context(), userId, data.Name, services.SearchCriteria{
+		Query:      data.Query,
+		CategoryId: data.CategoryID,
+		SellerId:   data.SellerID,
+		MinPrice:   data.MinPrice,
+		MaxPrice:   data.MaxPrice,
+	}, frequency)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrTooManySavedSearches):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrCategoryNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"category_id": "category does not exist"})
+		case errors.Is(err, services.ErrUserNotFound):
+			jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]string{"seller_id": "seller does not exist"})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, search)
+}
+
+func (api *Api) handleUpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	searchId, err := uuid.Parse(chi.URLParam(r, "search_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid search id - must be a valid uuid"})
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[user.UpdateSavedSearchReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	search, err := api.SavedSearchServices.UpdateSavedSearch(r.Context(), userId, searchId, data.Name, services.SearchFrequency(data.Frequency))
+	if err != nil {
+		if errors.Is(err, services.ErrSavedSearchNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, search)
+}
+
+func (api *Api) handleDeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	searchId, err := uuid.Parse(chi.URLParam(r, "search_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid search id - must be a valid uuid"})
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.SavedSearchServices.DeleteSavedSearch(r.Context(), userId, searchId); err != nil {
+		if errors.Is(err, services.ErrSavedSearchNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "saved search deleted"})
+}
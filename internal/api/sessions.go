@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/google/uuid"
+)
+
+// startSession logs userId in on the current session and records where the
+// login came from, call it after renewing the session token.
+func (api *Api) startSession(r *http.Request, userId uuid.UUID) error {
+	sessionId, err := api.UserServices.StartSession(r.Context(), userId, api.sessionToken(r.Context()), clientIP(r), r.UserAgent())
+	if err != nil {
+		return err
+	}
+	api.Sessions.Put(r.Context(), "AuthenticatedUserId", userId)
+	api.Sessions.Put(r.Context(), "SessionId", sessionId)
+	return nil
+}
+
+// sessionToken is the token and deadline of the cookie session in ctx.
+func (api *Api) sessionToken(ctx context.Context) services.SessionToken {
+	return services.SessionToken{Token: api.Sessions.Token(ctx), ExpiresAt: api.Sessions.Deadline(ctx)}
+}
+
+// currentSessionId is the id of the session the request came with, false for
+// requests authenticated with an access token.
+func (api *Api) currentSessionId(r *http.Request) (uuid.UUID, bool) {
+	if _, ok := r.Context().Value(tokenIdentityKey).(services.TokenIdentity); ok {
+		return uuid.Nil, false
+	}
+	sessionId, ok := api.Sessions.Get(r.Context(), "SessionId").(uuid.UUID)
+	return sessionId, ok
+}
+
+// revokeUserSessions destroys every session of userId except keep, pass
+// uuid.Nil to log the user out everywhere.
+func (api *Api) revokeUserSessions(ctx context.Context, userId, keep uuid.UUID) error {
+	if _, err := api.UserServices.RevokeSessions(ctx, userId, keep); err != nil {
+		return err
+	}
+	api.disconnectClients(func(c *services.Client) bool {
+		return c.UserId == userId && (keep == uuid.Nil || c.SessionId != keep)
+	}, "session revoked")
+	api.NotificationServices.CloseStreams(userId, func(sessionId uuid.UUID) bool {
+		return keep == uuid.Nil || sessionId != keep
+	}, "session revoked")
+	return nil
+}
+
+// revokeSession ends a single session and destroys its cookie session.
+func (api *Api) revokeSession(ctx context.Context, userId, sessionId uuid.UUID) error {
+	if err := api.UserServices.RevokeSession(ctx, userId, sessionId); err != nil {
+		return err
+	}
+	api.disconnectClients(func(c *services.Client) bool {
+		return c.SessionId == sessionId
+	}, "session revoked")
+	api.NotificationServices.CloseStreams(userId, func(id uuid.UUID) bool {
+		return id == sessionId
+	}, "session revoked")
+	return nil
+}
+
+// disconnectClients closes the auction websockets of the clients matching, in
+// every open room.
+func (api *Api) disconnectClients(match func(*services.Client) bool, reason string) {
+	api.AuctionLobby.Lock()
+	rooms := make([]*services.AuctionRoom, 0, len(api.AuctionLobby.Rooms))
+	for _, room := range api.AuctionLobby.Rooms {
+		rooms = append(rooms, room)
+	}
+	api.AuctionLobby.Unlock()
+
+	for _, room := range rooms {
+		room.DisconnectClients(match, reason)
+	}
+}
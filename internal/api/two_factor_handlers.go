@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/user"
+	"github.com/google/uuid"
+)
+
+// A login with 2FA enabled leaves a pending session that has to be completed
+// with a code before it expires.
+const (
+	pendingTwoFactorTTL         = 5 * time.Minute
+	pendingTwoFactorMaxAttempts = 5
+)
+
+func (api *Api) startPendingTwoFactor(ctx context.Context, userId uuid.UUID) {
+	api.Sessions.Put(ctx, "PendingTwoFactorUserId", userId)
+	api.Sessions.Put(ctx, "PendingTwoFactorExpiresAt", time.Now().Add(pendingTwoFactorTTL).Unix())
+	api.Sessions.Put(ctx, "PendingTwoFactorAttempts", 0)
+}
+
+func (api *Api) clearPendingTwoFactor(ctx context.Context) {
+	api.Sessions.Remove(ctx, "PendingTwoFactorUserId")
+	api.Sessions.Remove(ctx, "PendingTwoFactorExpiresAt")
+	api.Sessions.Remove(ctx, "PendingTwoFactorAttempts")
+}
+
+func (api *Api) handleLoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.LoginTwoFactorReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	userId, ok := api.Sessions.Get(r.Context(), "PendingTwoFactorUserId").(uuid.UUID)
+	expiresAt := api.Sessions.GetInt64(r.Context(), "PendingTwoFactorExpiresAt")
+	if !ok || time.Now().Unix() > expiresAt {
+		api.clearPendingTwoFactor(r.Context())
+		jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{"error": "log in with your email and password first"})
+		return
+	}
+
+	if err := api.UserServices.VerifySecondFactor(r.Context(), userId, data.Code, data.RecoveryCode); err != nil {
+		if !errors.Is(err, services.ErrInvalidTwoFactorCode) {
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+			return
+		}
+		if errors.Is(err, services.ErrTwoFactorLocked) {
+			api.clearPendingTwoFactor(r.Context())
+			jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{"error": err.Error()})
+			return
+		}
+		attempts := api.Sessions.GetInt(r.Context(), "PendingTwoFactorAttempts") + 1
+		if attempts >= pendingTwoFactorMaxAttempts {
+			api.clearPendingTwoFactor(r.Context())
+			jsonutils.EncodeJson(w, r, http.StatusUnauthorized, map[string]any{"error": "too many invalid codes, log in again"})
+			return
+		}
+		api.Sessions.Put(r.Context(), "PendingTwoFactorAttempts", attempts)
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+
+	if err := api.Sessions.RenewToken(r.Context()); err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+	api.clearPendingTwoFactor(r.Context())
+	if err := api.startSession(r, userId); err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "loged in, successfully"})
+}
+
+func (api *Api) handleGetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	status, err := api.UserServices.GetTwoFactorStatus(r.Context(), userId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, status)
+}
+
+func (api *Api) handleStartTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	enrolment, err := api.UserServices.StartTwoFactor(r.Context(), userId)
+	if err != nil {
+		encodeTwoFactorError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, enrolment)
+}
+
+func (api *Api) handleConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.TwoFactorCodeReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	codes, err := api.UserServices.ConfirmTwoFactor(r.Context(), userId, data.Code)
+	if err != nil {
+		encodeTwoFactorError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{
+		"message":        "two-factor authentication enabled, store the recovery codes somewhere safe",
+		"recovery_codes": codes,
+	})
+}
+
+func (api *Api) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.TwoFactorCodeReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	codes, err := api.UserServices.RegenerateRecoveryCodes(r.Context(), userId, data.Code)
+	if err != nil {
+		encodeTwoFactorError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"recovery_codes": codes})
+}
+
+func (api *Api) handleDisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.TwoFactorCodeReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	if err := api.UserServices.DisableTwoFactor(r.Context(), userId, data.Code); err != nil {
+		encodeTwoFactorError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "two-factor authentication disabled"})
+}
+
+func (api *Api) handleSetBidConfirmationThreshold(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[user.BidConfirmationThresholdReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	if err := api.UserServices.SetBidConfirmationThreshold(r.Context(), userId, data.Amount); err != nil {
+		encodeTwoFactorError(w, r, err)
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"bid_confirmation_threshold": data.Amount})
+}
+
+func encodeTwoFactorError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, services.ErrTwoFactorLocked):
+		jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorAlreadyEnabled):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrTwoFactorNotEnabled), errors.Is(err, services.ErrTwoFactorNotStarted):
+		jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+	case errors.Is(err, services.ErrUserNotFound):
+		jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+	default:
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+	}
+}
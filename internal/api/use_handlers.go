@@ -2,7 +2,11 @@ package api
 
 import (
 	"errors"
+	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 
 	"github.com/erikgmatos/gobid/internal/jsonutils"
 	"github.com/erikgmatos/gobid/internal/services"
@@ -21,6 +25,12 @@ func (api *Api) handleSignupUser(w http.ResponseWriter, r *http.Request) {
 			_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, map[string]any{"error": "username or email already exists"})
 			return
 		}
+		_ = jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+	// The account exists already, a failed email can be resent later.
+	if err := api.UserServices.SendVerificationEmail(r.Context(), id); err != nil {
+		slog.Error("Failed to send verification email", "user_id", id, "error", err)
 	}
 	_ = jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"user_id": id})
 }
@@ -31,12 +41,29 @@ func (api *Api) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 		_ = jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
 		return
 	}
-	id, err := api.UserServices.AuthenticateUser(r.Context(), data.Email, data.Password)
+	id, err := api.UserServices.AuthenticateUser(r.Context(), data.Email, data.Password, clientIP(r))
 	if err != nil {
 		if errors.Is(err, services.ErrInvalidCredentials) {
 			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid email or password"})
 			return
 		}
+		if errors.Is(err, services.ErrAccountSuspended) {
+			jsonutils.EncodeJson(w, r, http.StatusForbidden, map[string]any{"error": err.Error(), "code": services.ErrCodeAccountSuspended})
+			return
+		}
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+			jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{"error": throttled.Error(), "retry_after": retryAfter})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
+
+	twoFactor, err := api.UserServices.TwoFactorEnabled(r.Context(), id)
+	if err != nil {
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
 		return
 	}
@@ -46,16 +73,79 @@ func (api *Api) handleLoginUser(w http.ResponseWriter, r *http.Request) {
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
 		return
 	}
-	api.Sessions.Put(r.Context(), "AuthenticatedUserId", id)
+	if twoFactor {
+		api.startPendingTwoFactor(r.Context(), id)
+		jsonutils.EncodeJson(w, r, http.StatusAccepted, map[string]any{
+			"message":             "enter the code from your authenticator app",
+			"two_factor_required": true,
+		})
+		return
+	}
+	if err := api.startSession(r, id); err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+		return
+	}
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "loged in, successfully"})
 }
 
 func (api *Api) handleLogoutUser(w http.ResponseWriter, r *http.Request) {
+	userId, _ := api.authenticatedUserId(r)
+	if sessionId, ok := api.currentSessionId(r); ok {
+		if err := api.revokeSession(r.Context(), userId, sessionId); err != nil && !errors.Is(err, services.ErrSessionNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
+			return
+		}
+	}
 	err := api.Sessions.RenewToken(r.Context())
 	if err != nil {
 		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "internal server error"})
 		return
 	}
 	api.Sessions.Remove(r.Context(), "AuthenticatedUserId")
+	api.Sessions.Remove(r.Context(), "SessionId")
 	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "logged out successfully"})
 }
+
+func (api *Api) handleResendVerification(w http.ResponseWriter, r *http.Request) {
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	if err := api.UserServices.SendVerificationEmail(r.Context(), userId); err != nil {
+		switch {
+		case errors.Is(err, services.ErrVerificationThrottled):
+			w.Header().Set("Retry-After", strconv.Itoa(int(services.VerificationResendInterval.Seconds())))
+			jsonutils.EncodeJson(w, r, http.StatusTooManyRequests, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusAccepted, map[string]any{"message": "verification email sent"})
+}
+
+func (api *Api) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if err := api.UserServices.VerifyEmail(r.Context(), r.URL.Query().Get("token")); err != nil {
+		if errors.Is(err, services.ErrInvalidVerificationToken) {
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "email verified"})
+}
+
+// clientIP is the address of the peer, without the port. Put the server
+// behind middleware.RealIP when a proxy sits in front of it.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/jsonutils"
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/usecase/product"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+func (api *Api) handleSetVisibility(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[product.SetVisibilityReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	visibility, err := api.ProductServices.SetVisibility(r.Context(), productId, services.Visibility(data.Visibility))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProductNotFond):
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": "no product with given id"})
+		case errors.Is(err, services.ErrProductHasBids):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": "an auction cannot become invite only after the first bid"})
+		case errors.Is(err, services.ErrProductWithdrawn), errors.Is(err, services.ErrAuctionEnded):
+			jsonutils.EncodeJson(w, r, http.StatusConflict, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	// Only public auctions match saved searches.
+	if visibility == services.VisibilityPublic {
+		go api.SavedSearchServices.MatchProduct(productId)
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"visibility": visibility})
+}
+
+func (api *Api) handleListInviteLinks(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+
+	links, err := api.ProductServices.ListInviteLinks(r.Context(), productId)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"invite_links": links})
+}
+
+func (api *Api) handleCreateInviteLink(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	data, problems, err := jsonutils.DecodeValidJson[product.CreateInviteLinkReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+
+	ttl := services.DefaultInviteLinkTTL
+	if data.ExpiresInHours != nil {
+		ttl = time.Duration(*data.ExpiresInHours) * time.Hour
+	}
+	link, err := api.ProductServices.CreateInviteLink(r.Context(), productId, data.MaxUses, ttl)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusCreated, link)
+}
+
+func (api *Api) handleRevokeInviteLink(w http.ResponseWriter, r *http.Request) {
+	productId, ok := api.sellerProductId(w, r)
+	if !ok {
+		return
+	}
+	linkId, err := uuid.Parse(chi.URLParam(r, "link_id"))
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": "invalid link id - must be a valid uuid"})
+		return
+	}
+
+	if err := api.ProductServices.RevokeInviteLink(r.Context(), productId, linkId); err != nil {
+		if errors.Is(err, services.ErrInviteLinkNotFound) {
+			jsonutils.EncodeJson(w, r, http.StatusNotFound, map[string]any{"error": err.Error()})
+			return
+		}
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "invite link revoked"})
+}
+
+func (api *Api) handleRedeemInviteLink(w http.ResponseWriter, r *http.Request) {
+	data, problems, err := jsonutils.DecodeValidJson[product.RedeemInviteLinkReq](r)
+	if err != nil {
+		jsonutils.EncodeJson(w, r, http.StatusUnprocessableEntity, problems)
+		return
+	}
+	userId, ok := api.authenticatedUserId(r)
+	if !ok {
+		jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		return
+	}
+
+	productId, err := api.ProductServices.RedeemInviteLink(r.Context(), data.Token, userId)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidInviteLink):
+			jsonutils.EncodeJson(w, r, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		case errors.Is(err, services.ErrProductWithdrawn), errors.Is(err, services.ErrAuctionEnded):
+			jsonutils.EncodeJson(w, r, http.StatusGone, map[string]any{"error": err.Error()})
+		default:
+			jsonutils.EncodeJson(w, r, http.StatusInternalServerError, map[string]any{"error": "unexpected error, try again later"})
+		}
+		return
+	}
+	jsonutils.EncodeJson(w, r, http.StatusOK, map[string]any{"message": "you are invited to this auction", "product_id": productId})
+}
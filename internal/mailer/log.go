@@ -0,0 +1,15 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogMailer writes every email to the log instead of sending it. Useful for
+// development only, the body may contain secrets like reset links.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, m Message) error {
+	slog.Info("Email", "to", m.To, "subject", m.Subject, "body", m.Body)
+	return nil
+}
@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+)
+
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer sends through the server at addr (host:port), with plain
+// auth when a username is given.
+func NewSMTPMailer(addr, username, password, from string) (*SMTPMailer, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{addr: addr, from: from, auth: auth}, nil
+}
+
+func (sm *SMTPMailer) Send(ctx context.Context, m Message) error {
+	return smtp.SendMail(sm.addr, sm.auth, sm.from, []string{m.To}, format(sm.from, m))
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Scopes limit what a personal access token can do. Logged in users through
+// the session cookie have all of them.
+const (
+	ScopeRead = "read"
+	ScopeBid  = "bid"
+	ScopeSell = "sell"
+)
+
+var AccessTokenScopes = []string{ScopeRead, ScopeBid, ScopeSell}
+
+const (
+	accessTokenPrefix    = "gobid_pat_"
+	accessTokenShownSize = len(accessTokenPrefix) + 6
+)
+
+var (
+	ErrAccessTokenNotFound = errors.New("access token not found")
+	ErrInvalidAccessToken  = errors.New("access token is invalid, expired or revoked")
+)
+
+type AccessTokenService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewAccessTokenService(pool *pgxpool.Pool) AccessTokenService {
+	return AccessTokenService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+type AccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAccessToken carries the plain token, it is only available right
+// after creation.
+type CreatedAccessToken struct {
+	AccessToken
+	Token string `json:"token"`
+}
+
+// TokenIdentity is who a bearer token acts for.
+type TokenIdentity struct {
+	UserId uuid.UUID
+	Scopes []string
+}
+
+func (ts *AccessTokenService) CreateToken(ctx context.Context, userId uuid.UUID, name string, scopes []string, expiresAt *time.Time) (CreatedAccessToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return CreatedAccessToken{}, err
+	}
+	token := accessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	created, err := ts.queries.CreatePersonalAccessToken(ctx, pgstore.CreatePersonalAccessTokenParams{
+		UserID:      userId,
+		Name:        name,
+		TokenPrefix: token[:accessTokenShownSize],
+		TokenHash:   hashAccessToken(token),
+		Scopes:      scopes,
+		ExpiresAt:   optionalTime(expiresAt),
+	})
+	if err != nil {
+		return CreatedAccessToken{}, err
+	}
+	return CreatedAccessToken{AccessToken: accessToken(created), Token: token}, nil
+}
+
+func (ts *AccessTokenService) ListTokens(ctx context.Context, userId uuid.UUID) ([]AccessToken, error) {
+	rows, err := ts.queries.ListPersonalAccessTokens(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]AccessToken, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, accessToken(row))
+	}
+	return tokens, nil
+}
+
+func (ts *AccessTokenService) RevokeToken(ctx context.Context, userId, tokenId uuid.UUID) error {
+	revoked, err := ts.queries.RevokePersonalAccessToken(ctx, pgstore.RevokePersonalAccessTokenParams{
+		ID:     tokenId,
+		UserID: userId,
+	})
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves a bearer token and records its use.
+func (ts *AccessTokenService) Authenticate(ctx context.Context, token string) (TokenIdentity, error) {
+	if !strings.HasPrefix(token, accessTokenPrefix) {
+		return TokenIdentity{}, ErrInvalidAccessToken
+	}
+	row, err := ts.queries.UsePersonalAccessToken(ctx, hashAccessToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TokenIdentity{}, ErrInvalidAccessToken
+		}
+		return TokenIdentity{}, err
+	}
+	return TokenIdentity{UserId: row.UserID, Scopes: row.Scopes}, nil
+}
+
+func hashAccessToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+func accessToken(row pgstore.PersonalAccessToken) AccessToken {
+	return AccessToken{
+		ID:         row.ID,
+		Name:       row.Name,
+		Prefix:     row.TokenPrefix,
+		Scopes:     row.Scopes,
+		ExpiresAt:  nullableTime(row.ExpiresAt),
+		LastUsedAt: nullableTime(row.LastUsedAt),
+		CreatedAt:  row.CreatedAt,
+	}
+}
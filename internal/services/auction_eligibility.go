@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const ErrCodeNotEligible = "not_eligible"
+
+var ErrInvitationNotFound = errors.New("user is not invited to this auction")
+
+// EligibilityPolicy restricts who can bid on an auction, the zero value lets
+// everyone in.
+type EligibilityPolicy struct {
+	RequireVerifiedEmail bool   `json:"require_verified_email"`
+	MinAccountAgeDays    *int32 `json:"min_account_age_days"`
+	MinFeedbackScore     *int32 `json:"min_feedback_score"`
+	InviteOnly           bool   `json:"invite_only"`
+}
+
+// NotEligibleError tells a user why they cannot take part in an auction.
+type NotEligibleError struct {
+	Reason string
+}
+
+func (e *NotEligibleError) Error() string {
+	return e.Reason
+}
+
+type Invitation struct {
+	UserID    uuid.UUID `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	InvitedAt time.Time `json:"invited_at"`
+}
+
+func eligibilityPolicy(product pgstore.Product) EligibilityPolicy {
+	return EligibilityPolicy{
+		RequireVerifiedEmail: product.BiddersRequireVerifiedEmail,
+		MinAccountAgeDays:    nullableInt4(product.BiddersMinAccountAgeDays),
+		MinFeedbackScore:     nullableInt4(product.BiddersMinFeedbackScore),
+		InviteOnly:           product.BiddersInviteOnly,
+	}
+}
+
+// checkEligibility returns a *NotEligibleError when userId does not meet the
+// policy of product. The seller is always eligible to follow their auction.
+// Invite only auctions require an invitation whatever the policy says.
+func checkEligibility(ctx context.Context, queries *pgstore.Queries, product pgstore.Product, userId uuid.UUID) error {
+	if product.SellerID == userId {
+		return nil
+	}
+	policy := eligibilityPolicy(product)
+	policy.InviteOnly = policy.InviteOnly || Visibility(product.Visibility) == VisibilityInviteOnly
+	if policy == (EligibilityPolicy{}) {
+		return nil
+	}
+
+	user, err := queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if policy.RequireVerifiedEmail && !user.EmailVerifiedAt.Valid {
+		return &NotEligibleError{Reason: "this auction only accepts bidders with a verified email address"}
+	}
+	if policy.MinAccountAgeDays != nil {
+		ageDays := int32(time.Since(user.CreatedAt) / (24 * time.Hour))
+		if ageDays < *policy.MinAccountAgeDays {
+			return &NotEligibleError{Reason: fmt.Sprintf(
+				"this auction only accepts accounts at least %d days old, yours is %d days old",
+				*policy.MinAccountAgeDays, ageDays,
+			)}
+		}
+	}
+	if policy.MinFeedbackScore != nil {
+		reputation, err := getReputation(ctx, queries, userId)
+		if err != nil {
+			return err
+		}
+		if reputation.Score < int64(*policy.MinFeedbackScore) {
+			return &NotEligibleError{Reason: fmt.Sprintf(
+				"this auction only accepts bidders with a feedback score of at least %d, yours is %d",
+				*policy.MinFeedbackScore, reputation.Score,
+			)}
+		}
+	}
+	if policy.InviteOnly {
+		invited, err := queries.IsUserInvited(ctx, pgstore.IsUserInvitedParams{ProductID: product.ID, UserID: userId})
+		if err != nil {
+			return err
+		}
+		if !invited {
+			return &NotEligibleError{Reason: "this auction only accepts bids from users invited by the seller"}
+		}
+	}
+	return nil
+}
+
+func (ps *ProductService) GetEligibilityPolicy(ctx context.Context, productId uuid.UUID, viewerId *uuid.UUID) (EligibilityPolicy, error) {
+	product, err := ps.GetVisibleProduct(ctx, productId, viewerId)
+	if err != nil {
+		return EligibilityPolicy{}, err
+	}
+	return eligibilityPolicy(product), nil
+}
+
+// SetEligibilityPolicy replaces the policy of an open auction. Like the
+// other terms of the auction it is fixed once the first bid is placed.
+func (ps *ProductService) SetEligibilityPolicy(ctx context.Context, productId uuid.UUID, policy EligibilityPolicy) (EligibilityPolicy, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return EligibilityPolicy{}, err
+	}
+	defer tx.Rollback(ctx)
+	queries := ps.queries.WithTx(tx)
+
+	if _, err := lockOpenProduct(ctx, queries, productId); err != nil {
+		return EligibilityPolicy{}, err
+	}
+	bids, err := queries.CountBidsByProductId(ctx, productId)
+	if err != nil {
+		return EligibilityPolicy{}, err
+	}
+	if bids > 0 {
+		return EligibilityPolicy{}, ErrProductHasBids
+	}
+
+	product, err := queries.SetProductEligibility(ctx, pgstore.SetProductEligibilityParams{
+		ID:                          productId,
+		BiddersRequireVerifiedEmail: policy.RequireVerifiedEmail,
+		BiddersMinAccountAgeDays:    optionalInt4(policy.MinAccountAgeDays),
+		BiddersMinFeedbackScore:     optionalInt4(policy.MinFeedbackScore),
+		BiddersInviteOnly:           policy.InviteOnly,
+	})
+	if err != nil {
+		return EligibilityPolicy{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return EligibilityPolicy{}, err
+	}
+	return eligibilityPolicy(product), nil
+}
+
+// CheckEligibility is checkEligibility for callers outside of bidding, like
+// the auction room subscription.
+func (ps *ProductService) CheckEligibility(ctx context.Context, productId, userId uuid.UUID) error {
+	product, err := ps.GetProductById(ctx, productId)
+	if err != nil {
+		return err
+	}
+	return checkEligibility(ctx, ps.queries, product, userId)
+}
+
+func (ps *ProductService) InviteBidder(ctx context.Context, productId, userId uuid.UUID) error {
+	err := ps.queries.AddAuctionInvitation(ctx, pgstore.AddAuctionInvitationParams{ProductID: productId, UserID: userId})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (ps *ProductService) UninviteBidder(ctx context.Context, productId, userId uuid.UUID) error {
+	removed, err := ps.queries.RemoveAuctionInvitation(ctx, pgstore.RemoveAuctionInvitationParams{ProductID: productId, UserID: userId})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+func (ps *ProductService) ListInvitations(ctx context.Context, productId uuid.UUID) ([]Invitation, error) {
+	rows, err := ps.queries.ListAuctionInvitations(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	invitations := make([]Invitation, 0, len(rows))
+	for _, row := range rows {
+		invitations = append(invitations, Invitation{
+			UserID:    row.UserID,
+			UserName:  row.UserName,
+			InvitedAt: row.CreatedAt,
+		})
+	}
+	return invitations, nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"sync"
@@ -27,18 +28,47 @@ const (
 	//Errors
 	FailedToPlaceBid
 	InvalidJSON
+
+	//Request
+	TimeSync
+
+	//Info
+	TimeSyncReply
+	CountdownTick
+	RoomSnapshot
+	AuctionWithdrawn
 )
 
 type Message struct {
 	Message string      `json:"message,omitempty"`
 	Amount  float64     `json:"amount,omitempty"`
 	Kind    MessageKind `json:"kind"`
+	Code    string      `json:"code,omitempty"`
 	UserId  uuid.UUID   `json:"user_id,omitempty"`
+
+	// Times are unix milliseconds. ClientTime is echoed back on TimeSyncReply
+	// so the client can estimate its clock offset from the server.
+	ClientTime       int64 `json:"client_time,omitempty"`
+	ServerReceivedAt int64 `json:"server_received_at,omitempty"`
+	ServerTime       int64 `json:"server_time,omitempty"`
+	RemainingMs      int64 `json:"remaining_ms,omitempty"`
+
+	Snapshot *AuctionSnapshot `json:"snapshot,omitempty"`
+
+	// Otp is the 2FA code sent with bids above the bidder confirmation
+	// threshold.
+	Otp string `json:"otp,omitempty"`
 }
 
 type AuctionLobby struct {
 	sync.Mutex
-	Rooms map[uuid.UUID]*AuctionRoom
+	Rooms      map[uuid.UUID]*AuctionRoom
+	RoomConfig AuctionRoomConfig
+}
+
+type AuctionRoomConfig struct {
+	BidRateLimit BidRateLimit
+	Countdown    Countdown
 }
 
 type AuctionRoom struct {
@@ -48,17 +78,76 @@ type AuctionRoom struct {
 	Register   chan *Client
 	Unregister chan *Client
 	Clients    map[uuid.UUID]*Client
+	// Updates receives the new state of the product after the seller edits
+	// it, the room moves its end to the new auction_end.
+	Updates chan AuctionSnapshot
+	// Disconnects closes the connections of the matching clients, used when
+	// their session is revoked.
+	Disconnects chan ClientDisconnect
 
-	BidsServices BidsService
+	BidsServices  BidsService
+	Notifications NotificationService
+
+	bidLimiters *bidLimiters
+	countdown   Countdown
+	endAt       time.Time
+	cancel      context.CancelCauseFunc
+}
+
+type auctionWithdrawnError struct {
+	reason string
+}
+
+func (e *auctionWithdrawnError) Error() string {
+	return "auction withdrawn: " + e.reason
+}
+
+// Update sends the edited product to the room, it is a no-op once the room
+// has stopped.
+func (ar *AuctionRoom) Update(snapshot AuctionSnapshot) {
+	select {
+	case ar.Updates <- snapshot:
+	case <-ar.Context.Done():
+	}
+}
+
+type ClientDisconnect struct {
+	Match  func(*Client) bool
+	Reason string
+}
+
+// DisconnectClients force closes the connections of the clients for which
+// match returns true, it is a no-op once the room has stopped.
+func (ar *AuctionRoom) DisconnectClients(match func(*Client) bool, reason string) {
+	select {
+	case ar.Disconnects <- ClientDisconnect{Match: match, Reason: reason}:
+	case <-ar.Context.Done():
+	}
+}
+
+// Withdraw stops the auction before its end, connected clients are told the
+// reason.
+func (ar *AuctionRoom) Withdraw(reason string) {
+	ar.cancel(&auctionWithdrawnError{reason: reason})
 }
 
 func (ar *AuctionRoom) registerClient(c *Client) {
 	slog.Info("New user connected", "Client", c)
 	ar.Clients[c.UserId] = c
+	c.Send <- ar.countdownTick()
 }
 
 func (ar *AuctionRoom) unRegisterClient(c *Client) {
 	slog.Info("User disconnected", "Client", c)
+	if current, ok := ar.Clients[c.UserId]; ok && current == c {
+		delete(ar.Clients, c.UserId)
+	}
+}
+
+func (ar *AuctionRoom) disconnectClient(c *Client, code int, reason string) {
+	slog.Info("Disconnecting user", "RoomID", ar.Id, "user_id", c.UserId, "reason", reason)
+	c.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(writeWait))
+	c.Conn.Close()
 	delete(ar.Clients, c.UserId)
 }
 
@@ -66,16 +155,56 @@ func (ar *AuctionRoom) broadcastMessage(m Message) {
 	slog.Info("New message received", "RoomID", ar.Id, "Message", m.Message, "user_id", m.UserId)
 	switch m.Kind {
 	case PlaceBid:
-		bid, err := ar.BidsServices.PlaceBid(ar.Context, ar.Id, m.UserId, m.Amount)
+		allowed, exceeded := ar.bidLimiters.allow(m.UserId)
+		if !allowed {
+			client, ok := ar.Clients[m.UserId]
+			if !ok {
+				return
+			}
+			if exceeded {
+				ar.disconnectClient(client, websocket.ClosePolicyViolation, "too many bids")
+				return
+			}
+			client.Send <- Message{Message: "you are placing bids too fast, slow down", Kind: FailedToPlaceBid, Code: ErrCodeRateLimited, UserId: m.UserId}
+			return
+		}
+
+		bid, err := ar.BidsServices.PlaceBid(ar.Context, ar.Id, m.UserId, m.Amount, m.Otp)
 		if err != nil {
-			if errors.Is(err, ErrBidIsToLow) {
-				if client, ok := ar.Clients[m.UserId]; ok {
-					client.Send <- Message{Message: ErrBidIsToLow.Error(), Kind: FailedToPlaceBid, UserId: m.UserId}
-				}
+			client, ok := ar.Clients[m.UserId]
+			if !ok {
+				return
+			}
+			var notEligible *NotEligibleError
+			switch {
+			case errors.As(err, &notEligible):
+				client.Send <- Message{Message: notEligible.Reason, Kind: FailedToPlaceBid, Code: ErrCodeNotEligible, UserId: m.UserId}
+			case errors.Is(err, ErrBidIsToLow):
+				client.Send <- Message{Message: ErrBidIsToLow.Error(), Kind: FailedToPlaceBid, UserId: m.UserId}
+			case errors.Is(err, ErrSelfBid):
+				client.Send <- Message{Message: err.Error(), Kind: FailedToPlaceBid, Code: ErrCodeSelfBid, UserId: m.UserId}
+			case errors.Is(err, ErrRelatedAccountBid):
+				client.Send <- Message{Message: err.Error(), Kind: FailedToPlaceBid, Code: ErrCodeRelatedAccount, UserId: m.UserId}
+			case errors.Is(err, ErrBlockedBySeller):
+				client.Send <- Message{Message: err.Error(), Kind: FailedToPlaceBid, Code: ErrCodeBlockedBySeller, UserId: m.UserId}
+			case errors.Is(err, ErrAccountSuspended):
+				client.Send <- Message{Message: err.Error(), Kind: FailedToPlaceBid, Code: ErrCodeAccountSuspended, UserId: m.UserId}
+			case errors.Is(err, ErrPermissionDenied):
+				client.Send <- Message{Message: err.Error(), Kind: FailedToPlaceBid, Code: ErrCodePermissionDenied, UserId: m.UserId}
+			case errors.Is(err, ErrEmailNotVerified):
+				client.Send <- Message{Message: ErrEmailNotVerified.Error(), Kind: FailedToPlaceBid, Code: ErrCodeEmailNotVerified, UserId: m.UserId}
+			case errors.Is(err, ErrBidConfirmationRequired):
+				client.Send <- Message{Message: err.Error(), Kind: FailedToPlaceBid, Code: ErrCodeTwoFactorRequired, UserId: m.UserId}
+			case errors.Is(err, ErrInvalidTwoFactorCode):
+				client.Send <- Message{Message: err.Error(), Kind: FailedToPlaceBid, Code: ErrCodeInvalidTwoFactor, UserId: m.UserId}
+			case errors.Is(err, ErrAuctionEnded), errors.Is(err, ErrProductWithdrawn), errors.Is(err, ErrProductNotFond):
+				client.Send <- Message{Message: err.Error(), Kind: FailedToPlaceBid, UserId: m.UserId}
 			}
 			return
 		}
 
+		go ar.Notifications.NotifyOutbid(bid)
+
 		if client, ok := ar.Clients[m.UserId]; ok {
 			client.Send <- Message{Message: "Your bid was ssuccessfully placed", Kind: SuccessfullyPlaceBid, UserId: m.UserId}
 		}
@@ -88,6 +217,18 @@ func (ar *AuctionRoom) broadcastMessage(m Message) {
 			client.Send <- newBidPalced
 		}
 
+	case TimeSync:
+		client, ok := ar.Clients[m.UserId]
+		if !ok {
+			return
+		}
+		client.Send <- Message{
+			Kind:             TimeSyncReply,
+			UserId:           m.UserId,
+			ClientTime:       m.ClientTime,
+			ServerReceivedAt: m.ServerReceivedAt,
+		}
+
 	case InvalidJSON:
 		client, ok := ar.Clients[m.UserId]
 		if !ok {
@@ -98,16 +239,51 @@ func (ar *AuctionRoom) broadcastMessage(m Message) {
 	}
 }
 
+func (ar *AuctionRoom) finish(m Message) {
+	for _, client := range ar.Clients {
+		client.Send <- m
+	}
+}
+
 func (ar *AuctionRoom) Run() {
 	slog.Info("Auction has begun", "AuctionId", ar.Id)
+	countdownTimer := time.NewTimer(ar.nextTick())
+	endTimer := time.NewTimer(time.Until(ar.endAt))
 	defer func() {
-		close(ar.Broadcast)
-		close(ar.Register)
-		close(ar.Unregister)
+		countdownTimer.Stop()
+		endTimer.Stop()
+		// Clients select on the context so they stop sending to the room.
+		ar.cancel(nil)
 	}()
 
 	for {
 		select {
+		case <-countdownTimer.C:
+			tick := ar.countdownTick()
+			for _, client := range ar.Clients {
+				client.Send <- tick
+			}
+			countdownTimer.Reset(ar.nextTick())
+		case snapshot := <-ar.Updates:
+			ar.endAt = snapshot.AuctionEnd
+			endTimer.Reset(time.Until(ar.endAt))
+			countdownTimer.Reset(ar.nextTick())
+			tick := ar.countdownTick()
+			for _, client := range ar.Clients {
+				client.Send <- Message{Kind: RoomSnapshot, Snapshot: &snapshot}
+				client.Send <- tick
+			}
+		case <-endTimer.C:
+			slog.Info("Auction has ended.", "AuctionID", ar.Id)
+			go ar.Notifications.NotifyAuctionEnded(ar.Id)
+			ar.finish(Message{Message: "Auction has been finished", Kind: AuctionFinished})
+			return
+		case disconnect := <-ar.Disconnects:
+			for _, client := range ar.Clients {
+				if disconnect.Match(client) {
+					ar.disconnectClient(client, websocket.ClosePolicyViolation, disconnect.Reason)
+				}
+			}
 		case client := <-ar.Register:
 			ar.registerClient(client)
 		case client := <-ar.Unregister:
@@ -115,24 +291,36 @@ func (ar *AuctionRoom) Run() {
 		case message := <-ar.Broadcast:
 			ar.broadcastMessage(message)
 		case <-ar.Context.Done():
-			slog.Info("Auction has ended.", "AuctionID", ar.Id)
-			for _, client := range ar.Clients {
-				client.Send <- Message{Message: "Auction has been finished", Kind: AuctionFinished}
+			var withdrawn *auctionWithdrawnError
+			if errors.As(context.Cause(ar.Context), &withdrawn) {
+				slog.Info("Auction has been withdrawn.", "AuctionID", ar.Id, "reason", withdrawn.reason)
+				ar.finish(Message{Message: withdrawn.reason, Kind: AuctionWithdrawn})
+				return
 			}
+			slog.Info("Auction has ended.", "AuctionID", ar.Id)
+			ar.finish(Message{Message: "Auction has been finished", Kind: AuctionFinished})
 			return
 		}
 	}
 }
 
-func NewAuctionRoom(ctx context.Context, id uuid.UUID, bidsServices BidsService) *AuctionRoom {
+func NewAuctionRoom(ctx context.Context, id uuid.UUID, auctionEnd time.Time, bidsServices BidsService, notifications NotificationService, config AuctionRoomConfig) *AuctionRoom {
+	ctx, cancel := context.WithCancelCause(ctx)
 	return &AuctionRoom{
-		Id:           id,
-		Broadcast:    make(chan Message),
-		Register:     make(chan *Client),
-		Unregister:   make(chan *Client),
-		Clients:      make(map[uuid.UUID]*Client),
-		Context:      ctx,
-		BidsServices: bidsServices,
+		Id:            id,
+		Broadcast:     make(chan Message),
+		Register:      make(chan *Client),
+		Unregister:    make(chan *Client),
+		Clients:       make(map[uuid.UUID]*Client),
+		Updates:       make(chan AuctionSnapshot),
+		Disconnects:   make(chan ClientDisconnect),
+		Context:       ctx,
+		BidsServices:  bidsServices,
+		Notifications: notifications,
+		bidLimiters:   newBidLimiters(config.BidRateLimit),
+		countdown:     config.Countdown,
+		endAt:         auctionEnd,
+		cancel:        cancel,
 	}
 }
 
@@ -141,14 +329,18 @@ type Client struct {
 	Conn   *websocket.Conn
 	Send   chan Message
 	UserId uuid.UUID
+	// SessionId is the session the client connected with, uuid.Nil when it
+	// used a personal access token.
+	SessionId uuid.UUID
 }
 
-func NewClient(room *AuctionRoom, conn *websocket.Conn, userId uuid.UUID) *Client {
+func NewClient(room *AuctionRoom, conn *websocket.Conn, userId, sessionId uuid.UUID) *Client {
 	return &Client{
-		Room:   room,
-		Conn:   conn,
-		Send:   make(chan Message, 512),
-		UserId: userId,
+		Room:      room,
+		Conn:      conn,
+		Send:      make(chan Message, 512),
+		UserId:    userId,
+		SessionId: sessionId,
 	}
 }
 
@@ -159,9 +351,24 @@ const (
 	pingPeriod     = (readDeadline * 9) / 10
 )
 
+// sendToRoom hands m to the room unless the room has already stopped.
+func (c *Client) sendToRoom(m Message) {
+	select {
+	case c.Room.Broadcast <- m:
+	case <-c.Room.Context.Done():
+	}
+}
+
+func (c *Client) unregister() {
+	select {
+	case c.Room.Unregister <- c:
+	case <-c.Room.Context.Done():
+	}
+}
+
 func (c *Client) ReadEventLoop() {
 	defer func() {
-		c.Room.Unregister <- c
+		c.unregister()
 		c.Conn.Close()
 	}()
 
@@ -181,10 +388,18 @@ func (c *Client) ReadEventLoop() {
 				slog.Error("Unexpected close error", "Error", err)
 				return
 			}
-			c.Room.Broadcast <- Message{Message: "this message should be a valid JSON", Kind: InvalidJSON, UserId: m.UserId}
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+				return
+			}
+			c.sendToRoom(Message{Message: "this message should be a valid JSON", Kind: InvalidJSON, UserId: m.UserId})
 			continue
 		}
-		c.Room.Broadcast <- m
+		if m.Kind == TimeSync {
+			m.ServerReceivedAt = time.Now().UnixMilli()
+		}
+		c.sendToRoom(m)
 	}
 }
 
@@ -202,14 +417,16 @@ func (c *Client) WriteEventLoop() {
 				c.Conn.WriteJSON(Message{Message: "Close websocket connection", Kind: websocket.CloseMessage})
 				return
 			}
-			if message.Kind == AuctionFinished {
-				close(c.Send)
-				return
+			if message.Kind == TimeSyncReply || message.Kind == CountdownTick {
+				message.ServerTime = time.Now().UnixMilli()
 			}
 			c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
 			err := c.Conn.WriteJSON(message)
 			if err != nil {
-				c.Room.Unregister <- c
+				c.unregister()
+				return
+			}
+			if message.Kind == AuctionFinished || message.Kind == AuctionWithdrawn {
 				return
 			}
 		case <-ticker.C:
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AuctionSnapshot is the state of an auction sent to a client as soon as it
+// joins the auction room.
+type AuctionSnapshot struct {
+	ProductId    uuid.UUID      `json:"product_id"`
+	SellerId     uuid.UUID      `json:"seller_id"`
+	ProductName  string         `json:"product_name"`
+	Description  string         `json:"description"`
+	BasePrice    float64        `json:"base_price"`
+	CurrentPrice float64        `json:"current_price"`
+	AuctionEnd   time.Time      `json:"auction_end"`
+	Images       []ProductImage `json:"images"`
+}
+
+func (ps *ProductService) GetAuctionSnapshot(ctx context.Context, productId uuid.UUID) (AuctionSnapshot, error) {
+	product, err := ps.GetProductById(ctx, productId)
+	if err != nil {
+		return AuctionSnapshot{}, err
+	}
+	if product.WithdrawnAt.Valid {
+		return AuctionSnapshot{}, ErrProductWithdrawn
+	}
+
+	highestBid, err := ps.queries.GetHighestBidByProductId(ctx, productId)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return AuctionSnapshot{}, err
+	}
+
+	images, err := ps.GetProductImages(ctx, productId)
+	if err != nil {
+		return AuctionSnapshot{}, err
+	}
+
+	return AuctionSnapshot{
+		ProductId:    product.ID,
+		SellerId:     product.SellerID,
+		ProductName:  product.ProductName,
+		Description:  product.Description,
+		BasePrice:    product.BasePrice,
+		CurrentPrice: max(product.BasePrice, highestBid.BidAmount),
+		AuctionEnd:   product.AuctionEnd,
+		Images:       images,
+	}, nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Visibility string
+
+const (
+	// VisibilityPublic auctions are listed in the catalog and search.
+	VisibilityPublic Visibility = "public"
+	// VisibilityUnlisted auctions are left out of the catalog and search,
+	// anyone with the link can still follow and bid.
+	VisibilityUnlisted Visibility = "unlisted"
+	// VisibilityInviteOnly auctions are hidden from everyone but the seller
+	// and the invited users.
+	VisibilityInviteOnly Visibility = "invite_only"
+)
+
+var Visibilities = []Visibility{VisibilityPublic, VisibilityUnlisted, VisibilityInviteOnly}
+
+const (
+	DefaultInviteLinkTTL = 7 * 24 * time.Hour
+	MaxInviteLinkTTL     = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidInviteLink  = errors.New("invite link is invalid or has expired")
+	ErrInviteLinkNotFound = errors.New("invite link not found")
+)
+
+type InviteLink struct {
+	ID        uuid.UUID  `json:"id"`
+	ProductID uuid.UUID  `json:"product_id"`
+	MaxUses   *int32     `json:"max_uses"`
+	UseCount  int32      `json:"use_count"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CreatedInviteLink carries the link with the plain token, it is only
+// available right after creation.
+type CreatedInviteLink struct {
+	InviteLink
+	URL string `json:"url"`
+}
+
+func inviteLink(row pgstore.AuctionInviteLink) InviteLink {
+	return InviteLink{
+		ID:        row.ID,
+		ProductID: row.ProductID,
+		MaxUses:   nullableInt4(row.MaxUses),
+		UseCount:  row.UseCount,
+		ExpiresAt: row.ExpiresAt,
+		RevokedAt: nullableTime(row.RevokedAt),
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// canView reports whether viewerId, nil for anonymous requests, may see
+// product. Unlisted auctions are visible to anyone who knows the id.
+func canView(ctx context.Context, queries *pgstore.Queries, product pgstore.Product, viewerId *uuid.UUID) (bool, error) {
+	if Visibility(product.Visibility) != VisibilityInviteOnly {
+		return true, nil
+	}
+	if viewerId == nil {
+		return false, nil
+	}
+	if product.SellerID == *viewerId {
+		return true, nil
+	}
+	return queries.IsUserInvited(ctx, pgstore.IsUserInvitedParams{ProductID: product.ID, UserID: *viewerId})
+}
+
+// GetVisibleProduct is GetProductById for products shown to viewerId, hidden
+// auctions are reported as not found so their existence is not revealed.
+func (ps *ProductService) GetVisibleProduct(ctx context.Context, productId uuid.UUID, viewerId *uuid.UUID) (pgstore.Product, error) {
+	product, err := ps.GetProductById(ctx, productId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	visible, err := canView(ctx, ps.queries, product, viewerId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	if !visible {
+		return pgstore.Product{}, ErrProductNotFond
+	}
+	return product, nil
+}
+
+// SetVisibility changes who can find an open auction. Making it invite only
+// is refused after the first bid, the bidders so far were not invited.
+func (ps *ProductService) SetVisibility(ctx context.Context, productId uuid.UUID, visibility Visibility) (Visibility, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+	queries := ps.queries.WithTx(tx)
+
+	if _, err := lockOpenProduct(ctx, queries, productId); err != nil {
+		return "", err
+	}
+	if visibility == VisibilityInviteOnly {
+		bids, err := queries.CountBidsByProductId(ctx, productId)
+		if err != nil {
+			return "", err
+		}
+		if bids > 0 {
+			return "", ErrProductHasBids
+		}
+	}
+
+	product, err := queries.SetProductVisibility(ctx, pgstore.SetProductVisibilityParams{ID: productId, Visibility: string(visibility)})
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+	return Visibility(product.Visibility), nil
+}
+
+// CreateInviteLink returns a link that invites whoever opens it, at most
+// maxUses times when it is not nil.
+func (ps *ProductService) CreateInviteLink(ctx context.Context, productId uuid.UUID, maxUses *int32, ttl time.Duration) (CreatedInviteLink, error) {
+	token, hash, err := newInviteToken()
+	if err != nil {
+		return CreatedInviteLink{}, err
+	}
+	row, err := ps.queries.CreateAuctionInviteLink(ctx, pgstore.CreateAuctionInviteLinkParams{
+		ProductID: productId,
+		TokenHash: hash,
+		MaxUses:   optionalInt4(maxUses),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return CreatedInviteLink{}, err
+	}
+	return CreatedInviteLink{
+		InviteLink: inviteLink(row),
+		URL:        fmt.Sprintf("%s/auctions/%s/join?token=%s", ps.appURL, productId, token),
+	}, nil
+}
+
+func (ps *ProductService) ListInviteLinks(ctx context.Context, productId uuid.UUID) ([]InviteLink, error) {
+	rows, err := ps.queries.ListAuctionInviteLinks(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	links := make([]InviteLink, 0, len(rows))
+	for _, row := range rows {
+		links = append(links, inviteLink(row))
+	}
+	return links, nil
+}
+
+// RevokeInviteLink stops a link from inviting anyone else, the users who
+// already joined through it stay invited.
+func (ps *ProductService) RevokeInviteLink(ctx context.Context, productId, linkId uuid.UUID) error {
+	revoked, err := ps.queries.RevokeAuctionInviteLink(ctx, pgstore.RevokeAuctionInviteLinkParams{ID: linkId, ProductID: productId})
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrInviteLinkNotFound
+	}
+	return nil
+}
+
+// RedeemInviteLink invites userId to the auction of the link and returns its
+// id. Opening a link again does not count as another use, links of auctions
+// that ended or were withdrawn no longer work.
+func (ps *ProductService) RedeemInviteLink(ctx context.Context, token string, userId uuid.UUID) (uuid.UUID, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	defer tx.Rollback(ctx)
+	queries := ps.queries.WithTx(tx)
+
+	link, err := queries.GetAuctionInviteLinkForUpdate(ctx, hashInviteToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrInvalidInviteLink
+		}
+		return uuid.UUID{}, err
+	}
+	if link.RevokedAt.Valid || !time.Now().Before(link.ExpiresAt) {
+		return uuid.UUID{}, ErrInvalidInviteLink
+	}
+
+	product, err := lockOpenProduct(ctx, queries, link.ProductID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	if product.SellerID == userId {
+		return product.ID, nil
+	}
+	invited, err := queries.IsUserInvited(ctx, pgstore.IsUserInvitedParams{ProductID: product.ID, UserID: userId})
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	if invited {
+		return product.ID, nil
+	}
+	if link.MaxUses.Valid && link.UseCount >= link.MaxUses.Int32 {
+		return uuid.UUID{}, ErrInvalidInviteLink
+	}
+
+	if err := queries.AddAuctionInvitation(ctx, pgstore.AddAuctionInvitationParams{ProductID: product.ID, UserID: userId}); err != nil {
+		return uuid.UUID{}, err
+	}
+	if err := queries.IncrementAuctionInviteLinkUses(ctx, link.ID); err != nil {
+		return uuid.UUID{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, err
+	}
+	return product.ID, nil
+}
+
+// newInviteToken returns the token put in the link and the hash stored in
+// the database.
+func newInviteToken() (string, []byte, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashInviteToken(token), nil
+}
+
+func hashInviteToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+)
+
+func TestCanView(t *testing.T) {
+	sellerId := uuid.New()
+	viewerId := uuid.New()
+	product := func(v Visibility) pgstore.Product {
+		return pgstore.Product{ID: uuid.New(), SellerID: sellerId, Visibility: string(v)}
+	}
+
+	tests := []struct {
+		name      string
+		product   pgstore.Product
+		viewerId  *uuid.UUID
+		invited   *fakeRow
+		want      bool
+		wantErr   error
+		wantQuery bool
+	}{
+		{"public to anonymous", product(VisibilityPublic), nil, nil, true, nil, false},
+		{"unlisted to anonymous", product(VisibilityUnlisted), nil, nil, true, nil, false},
+		{"invite only to anonymous", product(VisibilityInviteOnly), nil, nil, false, nil, false},
+		{"invite only to seller", product(VisibilityInviteOnly), &sellerId, nil, true, nil, false},
+		{"invite only to invited user", product(VisibilityInviteOnly), &viewerId, &fakeRow{values: []any{true}}, true, nil, true},
+		{"invite only to other user", product(VisibilityInviteOnly), &viewerId, &fakeRow{values: []any{false}}, false, nil, true},
+		{"invitation lookup fails", product(VisibilityInviteOnly), &viewerId, &fakeRow{err: errFakeDB}, false, errFakeDB, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{rows: map[string]fakeRow{}}
+			if tt.invited != nil {
+				db.rows["IsUserInvited"] = *tt.invited
+			}
+			got, err := canView(context.Background(), pgstore.New(db), tt.product, tt.viewerId)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			if queried := db.calls["IsUserInvited"] > 0; queried != tt.wantQuery {
+				t.Errorf("queried invitations = %v, want %v", queried, tt.wantQuery)
+			}
+		})
+	}
+}
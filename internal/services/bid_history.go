@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// priceHistoryPoints is the number of buckets the auction duration is split
+// into for the price over time summary.
+const priceHistoryPoints = 24
+
+// HistoryBid is a bid as shown to everyone, the bidder is only identified by
+// the order in which they joined the auction.
+type HistoryBid struct {
+	ID          uuid.UUID `json:"id"`
+	BidderLabel string    `json:"bidder_label"`
+	Amount      float64   `json:"amount"`
+	PlacedAt    time.Time `json:"placed_at"`
+}
+
+type PricePoint struct {
+	At    time.Time `json:"at"`
+	Price float64   `json:"price"`
+}
+
+// PriceSummary describes how the price moved during an auction that has
+// ended.
+type PriceSummary struct {
+	StartingPrice float64      `json:"starting_price"`
+	FinalPrice    float64      `json:"final_price"`
+	BidCount      int          `json:"bid_count"`
+	BidderCount   int          `json:"bidder_count"`
+	Points        []PricePoint `json:"points"`
+}
+
+type BidHistoryPage struct {
+	Bids       []HistoryBid  `json:"bids"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Summary    *PriceSummary `json:"summary,omitempty"`
+}
+
+// GetBidHistory returns the bids of a product, newest first. The summary is
+// only filled on the first page of an auction that has ended.
+func (bs *BidsService) GetBidHistory(ctx context.Context, productId uuid.UUID, cursor string, limit int32) (BidHistoryPage, error) {
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return BidHistoryPage{}, err
+	}
+
+	product, err := bs.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BidHistoryPage{}, ErrProductNotFond
+		}
+		return BidHistoryPage{}, err
+	}
+	if product.WithdrawnAt.Valid {
+		return BidHistoryPage{}, ErrProductWithdrawn
+	}
+
+	params := pgstore.ListBidHistoryParams{ProductID: productId, PageSize: limit + 1}
+	params.CursorCreatedAt, params.CursorID = after.params()
+	rows, err := bs.queries.ListBidHistory(ctx, params)
+	if err != nil {
+		return BidHistoryPage{}, err
+	}
+
+	page := BidHistoryPage{Bids: make([]HistoryBid, 0, len(rows))}
+	for i, r := range rows {
+		if int32(i) == limit {
+			last := rows[i-1]
+			page.NextCursor = encodeKeysetCursor(last.ID, last.CreatedAt)
+			break
+		}
+		page.Bids = append(page.Bids, HistoryBid{
+			ID:          r.ID,
+			BidderLabel: fmt.Sprintf("Bidder %d", r.BidderNumber),
+			Amount:      r.BidAmount,
+			PlacedAt:    r.CreatedAt,
+		})
+	}
+
+	ended := product.IsSold || !product.AuctionEnd.After(time.Now())
+	if ended && after == nil {
+		bids, err := bs.queries.GetBidsByProductId(ctx, productId)
+		if err != nil {
+			return BidHistoryPage{}, err
+		}
+		summary := summarizePrices(product, bids)
+		page.Summary = &summary
+	}
+	return page, nil
+}
+
+// summarizePrices splits the auction in equal buckets and takes the price at
+// the end of each one. bids come sorted by amount, highest first, which is
+// also the reverse order in which they were placed.
+func summarizePrices(product pgstore.Product, bids []pgstore.Bid) PriceSummary {
+	summary := PriceSummary{
+		StartingPrice: product.BasePrice,
+		FinalPrice:    product.BasePrice,
+		BidCount:      len(bids),
+		Points:        make([]PricePoint, 0, priceHistoryPoints+1),
+	}
+
+	bidders := make(map[uuid.UUID]struct{}, len(bids))
+	for _, b := range bids {
+		bidders[b.BidderID] = struct{}{}
+	}
+	summary.BidderCount = len(bidders)
+	if len(bids) > 0 {
+		summary.FinalPrice = bids[0].BidAmount
+	}
+
+	start, end := product.CreatedAt, product.AuctionEnd
+	step := end.Sub(start) / priceHistoryPoints
+	price := product.BasePrice
+	next := len(bids) - 1
+	for i := 0; i <= priceHistoryPoints; i++ {
+		at := start.Add(step * time.Duration(i))
+		if i == priceHistoryPoints {
+			at = end
+		}
+		for next >= 0 && !bids[next].CreatedAt.After(at) {
+			price = bids[next].BidAmount
+			next--
+		}
+		summary.Points = append(summary.Points, PricePoint{At: at, Price: price})
+	}
+	return summary
+}
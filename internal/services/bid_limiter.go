@@ -0,0 +1,61 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+)
+
+const ErrCodeRateLimited = "rate_limited"
+
+type BidRateLimit struct {
+	// BidsPerSecond is the rate at which a user's bid tokens are refilled.
+	BidsPerSecond float64
+	// Burst is the maximum number of bids a user can place at once.
+	Burst int
+	// MaxViolations is how many limited bids in a row a user may send
+	// before being disconnected from the room. Zero never disconnects.
+	MaxViolations int
+}
+
+var DefaultBidRateLimit = BidRateLimit{
+	BidsPerSecond: 1,
+	Burst:         3,
+	MaxViolations: 10,
+}
+
+type bidLimiter struct {
+	limiter    *rate.Limiter
+	violations int
+}
+
+// bidLimiters keeps one token bucket per user of a room. It is only touched
+// from the room's Run goroutine so it needs no locking.
+type bidLimiters struct {
+	config   BidRateLimit
+	limiters map[uuid.UUID]*bidLimiter
+}
+
+func newBidLimiters(config BidRateLimit) *bidLimiters {
+	return &bidLimiters{
+		config:   config,
+		limiters: make(map[uuid.UUID]*bidLimiter),
+	}
+}
+
+// allow reports whether the user may place a bid now and whether the user has
+// exceeded the allowed number of consecutive violations.
+func (bl *bidLimiters) allow(userId uuid.UUID) (allowed bool, exceeded bool) {
+	l, ok := bl.limiters[userId]
+	if !ok {
+		l = &bidLimiter{limiter: rate.NewLimiter(rate.Limit(bl.config.BidsPerSecond), bl.config.Burst)}
+		bl.limiters[userId] = l
+	}
+
+	if l.limiter.Allow() {
+		l.violations = 0
+		return true, false
+	}
+
+	l.violations++
+	return false, bl.config.MaxViolations > 0 && l.violations >= bl.config.MaxViolations
+}
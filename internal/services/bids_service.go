@@ -24,18 +24,57 @@ func NewBidsService(pool *pgxpool.Pool) BidsService {
 	}
 }
 
+// PlaceBid runs with the product row locked, so an edit or a withdrawal of
+// the product cannot interleave with the bid.
 func (bs *BidsService) PlaceBid(
 	ctx context.Context,
 	product_id, bidder_id uuid.UUID,
 	amount float64,
+	otp string,
 ) (pgstore.Bid, error) {
-	product, err := bs.queries.GetProductById(ctx, product_id)
+	tx, err := bs.pool.Begin(ctx)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return pgstore.Bid{}, nil
-		}
+		return pgstore.Bid{}, err
+	}
+	defer tx.Rollback(ctx)
+	queries := bs.queries.WithTx(tx)
+
+	product, err := lockOpenProduct(ctx, queries, product_id)
+	if err != nil {
+		return pgstore.Bid{}, err
+	}
+	if product.SellerID == bidder_id {
+		return pgstore.Bid{}, ErrSelfBid
 	}
-	highestBid, err := bs.queries.GetHighestBidByProductId(ctx, product_id)
+	related, err := queries.IsConfirmedShill(ctx, pgstore.IsConfirmedShillParams{SellerID: product.SellerID, BidderID: bidder_id})
+	if err != nil {
+		return pgstore.Bid{}, err
+	}
+	if related {
+		return pgstore.Bid{}, ErrRelatedAccountBid
+	}
+	blocked, err := queries.IsUserBlockedBySeller(ctx, pgstore.IsUserBlockedBySellerParams{SellerID: product.SellerID, BlockedUserID: bidder_id})
+	if err != nil {
+		return pgstore.Bid{}, err
+	}
+	if blocked {
+		return pgstore.Bid{}, ErrBlockedBySeller
+	}
+	if err := requirePermission(ctx, queries, bidder_id, PermBid); err != nil {
+		return pgstore.Bid{}, err
+	}
+	if err := checkEligibility(ctx, queries, product, bidder_id); err != nil {
+		return pgstore.Bid{}, err
+	}
+	if err := requireVerifiedEmail(ctx, queries, bidder_id); err != nil {
+		return pgstore.Bid{}, err
+	}
+	// The code is checked outside the transaction, the step it uses up must
+	// not be given back when the bid is rejected afterwards.
+	if err := requireBidConfirmation(ctx, bs.queries, bidder_id, amount, otp); err != nil {
+		return pgstore.Bid{}, err
+	}
+	highestBid, err := queries.GetHighestBidByProductId(ctx, product_id)
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
 			return pgstore.Bid{}, err
@@ -44,7 +83,7 @@ func (bs *BidsService) PlaceBid(
 	if product.BasePrice >= amount || highestBid.BidAmount >= amount {
 		return pgstore.Bid{}, ErrBidIsToLow
 	}
-	highestBid, err = bs.queries.CreateBid(ctx, pgstore.CreateBidParams{
+	highestBid, err = queries.CreateBid(ctx, pgstore.CreateBidParams{
 		ProductID: product_id,
 		BidderID:  bidder_id,
 		BidAmount: amount,
@@ -52,5 +91,9 @@ func (bs *BidsService) PlaceBid(
 	if err != nil {
 		return pgstore.Bid{}, err
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Bid{}, err
+	}
+	go bs.inspectBid(product_id, product.SellerID, bidder_id)
 	return highestBid, nil
 }
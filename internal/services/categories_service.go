@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CategoryService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewCategoryService(pool *pgxpool.Pool) CategoryService {
+	return CategoryService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+var (
+	ErrCategoryNotFound   = errors.New("category not found")
+	ErrDuplicatedCategory = errors.New("category slug already exists")
+	ErrCategoryCycle      = errors.New("a category cannot be moved under itself or one of its children")
+	ErrCategoryInUse      = errors.New("category still has children or products")
+	ErrTagNotFound        = errors.New("tag not found")
+)
+
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
+
+type CategoryNode struct {
+	ID       uuid.UUID       `json:"id"`
+	Name     string          `json:"name"`
+	Slug     string          `json:"slug"`
+	Children []*CategoryNode `json:"children"`
+}
+
+func (cs *CategoryService) CreateCategory(ctx context.Context, parentId *uuid.UUID, name, slug string) (pgstore.Category, error) {
+	category, err := cs.queries.CreateCategory(ctx, pgstore.CreateCategoryParams{
+		ParentID: optionalUUID(parentId),
+		Name:     name,
+		Slug:     slug,
+	})
+	if err != nil {
+		return pgstore.Category{}, categoryError(err)
+	}
+	return category, nil
+}
+
+func (cs *CategoryService) UpdateCategory(ctx context.Context, id uuid.UUID, parentId *uuid.UUID, name, slug string) (pgstore.Category, error) {
+	if parentId != nil {
+		cycle, err := cs.queries.IsCategoryInSubtree(ctx, pgstore.IsCategoryInSubtreeParams{
+			RootID:     id,
+			CategoryID: *parentId,
+		})
+		if err != nil {
+			return pgstore.Category{}, err
+		}
+		if cycle {
+			return pgstore.Category{}, ErrCategoryCycle
+		}
+	}
+
+	category, err := cs.queries.UpdateCategory(ctx, pgstore.UpdateCategoryParams{
+		ID:       id,
+		ParentID: optionalUUID(parentId),
+		Name:     name,
+		Slug:     slug,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Category{}, ErrCategoryNotFound
+		}
+		return pgstore.Category{}, categoryError(err)
+	}
+	return category, nil
+}
+
+func (cs *CategoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	deleted, err := cs.queries.DeleteCategory(ctx, id)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == pgForeignKeyViolation {
+			return ErrCategoryInUse
+		}
+		return err
+	}
+	if deleted == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// ListCategories returns the whole category tree, roots first.
+func (cs *CategoryService) ListCategories(ctx context.Context) ([]*CategoryNode, error) {
+	categories, err := cs.queries.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uuid.UUID]*CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &CategoryNode{ID: c.ID, Name: c.Name, Slug: c.Slug, Children: []*CategoryNode{}}
+	}
+
+	roots := []*CategoryNode{}
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if parent, ok := nodes[c.ParentID.Bytes]; c.ParentID.Valid && ok {
+			parent.Children = append(parent.Children, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+func (cs *CategoryService) CreateTag(ctx context.Context, name string) (uuid.UUID, error) {
+	return cs.queries.UpsertTag(ctx, normalizeTag(name))
+}
+
+func (cs *CategoryService) ListTags(ctx context.Context) ([]pgstore.Tag, error) {
+	tags, err := cs.queries.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []pgstore.Tag{}
+	}
+	return tags, nil
+}
+
+func (cs *CategoryService) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	deleted, err := cs.queries.DeleteTag(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrTagNotFound
+	}
+	return nil
+}
+
+func categoryError(err error) error {
+	var pgError *pgconn.PgError
+	if errors.As(err, &pgError) {
+		switch pgError.Code {
+		case pgUniqueViolation:
+			return ErrDuplicatedCategory
+		case pgForeignKeyViolation:
+			return ErrCategoryNotFound
+		}
+	}
+	return err
+}
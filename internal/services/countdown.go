@@ -0,0 +1,38 @@
+package services
+
+import "time"
+
+type Countdown struct {
+	// Interval is how often the room sends the remaining time to its clients.
+	Interval time.Duration
+	// FinalInterval replaces Interval once the auction enters FinalWindow.
+	FinalInterval time.Duration
+	FinalWindow   time.Duration
+}
+
+var DefaultCountdown = Countdown{
+	Interval:      15 * time.Second,
+	FinalInterval: time.Second,
+	FinalWindow:   time.Minute,
+}
+
+func (ar *AuctionRoom) remaining() time.Duration {
+	return max(time.Until(ar.endAt), 0)
+}
+
+// nextTick returns how long the room waits before sending the next countdown
+// tick, never overshooting the start of the final window.
+func (ar *AuctionRoom) nextTick() time.Duration {
+	remaining := ar.remaining()
+	if remaining <= ar.countdown.FinalWindow {
+		return ar.countdown.FinalInterval
+	}
+	return min(ar.countdown.Interval, remaining-ar.countdown.FinalWindow)
+}
+
+func (ar *AuctionRoom) countdownTick() Message {
+	return Message{
+		Kind:        CountdownTick,
+		RemainingMs: ar.remaining().Milliseconds(),
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursors are handed to clients as opaque strings, the base64 of their json.
+func encodeCursor(c any) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string, c any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, c); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// keysetCursor is the position of the last row of a page sorted by
+// created_at and id, newest first.
+type keysetCursor struct {
+	Id        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"ca"`
+}
+
+func encodeKeysetCursor(id uuid.UUID, createdAt time.Time) string {
+	return encodeCursor(keysetCursor{Id: id, CreatedAt: createdAt})
+}
+
+// decodeKeysetCursor returns nil for the empty cursor of the first page.
+func decodeKeysetCursor(s string) (*keysetCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var c keysetCursor
+	if err := decodeCursor(s, &c); err != nil {
+		return nil, err
+	}
+	if c.Id == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// params returns the cursor_created_at and cursor_id arguments of the
+// keyset queries, both NULL when c is nil.
+func (c *keysetCursor) params() (pgtype.Timestamptz, pgtype.UUID) {
+	if c == nil {
+		return pgtype.Timestamptz{}, pgtype.UUID{}
+	}
+	return pgtype.Timestamptz{Time: c.CreatedAt, Valid: true}, pgtype.UUID{Bytes: c.Id, Valid: true}
+}
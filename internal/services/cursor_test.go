@@ -0,0 +1,62 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestKeysetCursor(t *testing.T) {
+	want := keysetCursor{Id: uuid.New(), CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name    string
+		cursor  string
+		want    *keysetCursor
+		wantErr error
+	}{
+		{"empty", "", nil, nil},
+		{"round trip", encodeKeysetCursor(want.Id, want.CreatedAt), &want, nil},
+		{"not base64", "not a cursor!", nil, ErrInvalidCursor},
+		{"not json", raw("[1,2]"), nil, ErrInvalidCursor},
+		{"without id", raw(`{"ca":"2024-05-01T12:00:00Z"}`), nil, ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeKeysetCursor(tt.cursor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("got %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetCursorParams(t *testing.T) {
+	var first *keysetCursor
+	createdAt, id := first.params()
+	if createdAt.Valid || id.Valid {
+		t.Errorf("first page params = %v, %v, want both NULL", createdAt, id)
+	}
+
+	c := &keysetCursor{Id: uuid.New(), CreatedAt: time.Now()}
+	createdAt, id = c.params()
+	if !createdAt.Valid || !createdAt.Time.Equal(c.CreatedAt) {
+		t.Errorf("cursor_created_at = %v, want %v", createdAt, c.CreatedAt)
+	}
+	if !id.Valid || id.Bytes != c.Id {
+		t.Errorf("cursor_id = %v, want %v", id, c.Id)
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeDB answers the single row queries of a test by their sqlc name and
+// counts how often each one ran.
+type fakeDB struct {
+	rows  map[string]fakeRow
+	calls map[string]int
+}
+
+type fakeRow struct {
+	values []any
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(dest) != len(r.values) {
+		return fmt.Errorf("scan into %d values, the row has %d", len(dest), len(r.values))
+	}
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.values[i]))
+	}
+	return nil
+}
+
+func queryName(sql string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(sql, "-- name: "), " ")
+	return name
+}
+
+func (db *fakeDB) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	name := queryName(sql)
+	if db.calls == nil {
+		db.calls = make(map[string]int)
+	}
+	db.calls[name]++
+	row, ok := db.rows[name]
+	if !ok {
+		return fakeRow{err: fmt.Errorf("unexpected query %s", name)}
+	}
+	return row
+}
+
+func (db *fakeDB) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, fmt.Errorf("unexpected query %s", queryName(sql))
+}
+
+func (db *fakeDB) Query(_ context.Context, sql string, _ ...any) (pgx.Rows, error) {
+	return nil, fmt.Errorf("unexpected query %s", queryName(sql))
+}
+
+var errFakeDB = errors.New("connection lost")
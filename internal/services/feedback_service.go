@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FeedbackRole string
+
+const (
+	FeedbackForBuyer  FeedbackRole = "buyer"
+	FeedbackForSeller FeedbackRole = "seller"
+)
+
+// Feedback scores, a reputation is the positive count minus the negative one.
+const (
+	FeedbackNegative int32 = -1
+	FeedbackNeutral  int32 = 0
+	FeedbackPositive int32 = 1
+)
+
+// UnsettledFeedbackGrace is how long after the end of an auction its buyer
+// can rate a seller that never settled the sale.
+const UnsettledFeedbackGrace = 7 * 24 * time.Hour
+
+var (
+	ErrSaleNotSettled      = errors.New("feedback opens once the seller settles the sale")
+	ErrNotPartOfSale       = errors.New("only the buyer and the seller of a sale can leave feedback")
+	ErrFeedbackAlreadyLeft = errors.New("feedback for this sale was already left")
+)
+
+type FeedbackService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewFeedbackService(pool *pgxpool.Pool) FeedbackService {
+	return FeedbackService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+type FeedbackEntry struct {
+	ID            uuid.UUID    `json:"id"`
+	ProductID     uuid.UUID    `json:"product_id"`
+	ProductName   string       `json:"product_name"`
+	AuthorID      uuid.UUID    `json:"author_id"`
+	AuthorName    string       `json:"author_name"`
+	RecipientID   uuid.UUID    `json:"recipient_id"`
+	RecipientRole FeedbackRole `json:"recipient_role"`
+	Score         int32        `json:"score"`
+	Comment       string       `json:"comment"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+type FeedbackPage struct {
+	Feedback   []FeedbackEntry `json:"feedback"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type Reputation struct {
+	Score    int64 `json:"score"`
+	Positive int64 `json:"positive"`
+	Neutral  int64 `json:"neutral"`
+	Negative int64 `json:"negative"`
+	// PositivePercent leaves neutral feedback out, nil until the first
+	// positive or negative rating.
+	PositivePercent *float64 `json:"positive_percent"`
+}
+
+// LeaveFeedback rates the other party of a settled sale, the recipient is
+// the seller when authorId is the buyer and the other way round. The buyer
+// can also rate a sale that is still not settled UnsettledFeedbackGrace after
+// the end of the auction.
+func (fs *FeedbackService) LeaveFeedback(ctx context.Context, productId, authorId uuid.UUID, score int32, comment string) (FeedbackEntry, error) {
+	product, err := fs.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return FeedbackEntry{}, ErrProductNotFond
+		}
+		return FeedbackEntry{}, err
+	}
+	sale, err := fs.queries.GetSale(ctx, product.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return FeedbackEntry{}, ErrSaleNotSettled
+		}
+		return FeedbackEntry{}, err
+	}
+
+	var recipientId uuid.UUID
+	var role FeedbackRole
+	switch authorId {
+	case sale.BuyerID:
+		recipientId, role = sale.SellerID, FeedbackForSeller
+	case sale.SellerID:
+		recipientId, role = sale.BuyerID, FeedbackForBuyer
+	default:
+		return FeedbackEntry{}, ErrNotPartOfSale
+	}
+	if !sale.IsSold && (role == FeedbackForBuyer || time.Now().Before(sale.AuctionEnd.Add(UnsettledFeedbackGrace))) {
+		return FeedbackEntry{}, ErrSaleNotSettled
+	}
+
+	created, err := fs.queries.CreateFeedback(ctx, pgstore.CreateFeedbackParams{
+		ProductID:     product.ID,
+		AuthorID:      authorId,
+		RecipientID:   recipientId,
+		RecipientRole: string(role),
+		Score:         score,
+		Comment:       comment,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return FeedbackEntry{}, ErrFeedbackAlreadyLeft
+		}
+		return FeedbackEntry{}, err
+	}
+
+	author, err := fs.queries.GetUserById(ctx, authorId)
+	if err != nil {
+		return FeedbackEntry{}, err
+	}
+	return FeedbackEntry{
+		ID:            created.ID,
+		ProductID:     created.ProductID,
+		ProductName:   product.ProductName,
+		AuthorID:      created.AuthorID,
+		AuthorName:    author.UserName,
+		RecipientID:   created.RecipientID,
+		RecipientRole: FeedbackRole(created.RecipientRole),
+		Score:         created.Score,
+		Comment:       created.Comment,
+		CreatedAt:     created.CreatedAt,
+	}, nil
+}
+
+// ListUserFeedback returns the feedback userId received, newest first. role
+// narrows it to the sales where they were the buyer or the seller. Feedback
+// on auctions viewerId cannot see is left out, so a page can hold fewer
+// than limit entries.
+func (fs *FeedbackService) ListUserFeedback(ctx context.Context, userId uuid.UUID, viewerId *uuid.UUID, role FeedbackRole, cursor string, limit int32) (FeedbackPage, error) {
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return FeedbackPage{}, err
+	}
+	if _, err := fs.queries.GetUserById(ctx, userId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return FeedbackPage{}, ErrUserNotFound
+		}
+		return FeedbackPage{}, err
+	}
+
+	params := pgstore.ListFeedbackForUserParams{
+		RecipientID:   userId,
+		RecipientRole: pgtype.Text{String: string(role), Valid: role != ""},
+		PageSize:      limit + 1,
+	}
+	params.CursorCreatedAt, params.CursorID = after.params()
+	rows, err := fs.queries.ListFeedbackForUser(ctx, params)
+	if err != nil {
+		return FeedbackPage{}, err
+	}
+
+	page := FeedbackPage{Feedback: make([]FeedbackEntry, 0, len(rows))}
+	for i, r := range rows {
+		if int32(i) == limit {
+			last := rows[i-1]
+			page.NextCursor = encodeKeysetCursor(last.ID, last.CreatedAt)
+			break
+		}
+		visible, err := canView(ctx, fs.queries, pgstore.Product{
+			ID:         r.ProductID,
+			SellerID:   r.ProductSellerID,
+			Visibility: r.ProductVisibility,
+		}, viewerId)
+		if err != nil {
+			return FeedbackPage{}, err
+		}
+		if visible {
+			page.Feedback = append(page.Feedback, feedbackEntry(pgstore.ListFeedbackForProductRow(r)))
+		}
+	}
+	return page, nil
+}
+
+// ListProductFeedback returns the feedback left on the sale of a product,
+// at most one entry from each side.
+func (fs *FeedbackService) ListProductFeedback(ctx context.Context, productId uuid.UUID) ([]FeedbackEntry, error) {
+	rows, err := fs.queries.ListFeedbackForProduct(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]FeedbackEntry, 0, len(rows))
+	for _, r := range rows {
+		entries = append(entries, feedbackEntry(r))
+	}
+	return entries, nil
+}
+
+func feedbackEntry(row pgstore.ListFeedbackForProductRow) FeedbackEntry {
+	return FeedbackEntry{
+		ID:            row.ID,
+		ProductID:     row.ProductID,
+		ProductName:   row.ProductName,
+		AuthorID:      row.AuthorID,
+		AuthorName:    row.AuthorName,
+		RecipientID:   row.RecipientID,
+		RecipientRole: FeedbackRole(row.RecipientRole),
+		Score:         row.Score,
+		Comment:       row.Comment,
+		CreatedAt:     row.CreatedAt,
+	}
+}
+
+func getReputation(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID) (Reputation, error) {
+	counts, err := queries.GetUserReputation(ctx, userId)
+	if err != nil {
+		return Reputation{}, err
+	}
+	reputation := Reputation{
+		Score:    counts.Positive - counts.Negative,
+		Positive: counts.Positive,
+		Neutral:  counts.Neutral,
+		Negative: counts.Negative,
+	}
+	if rated := counts.Positive + counts.Negative; rated > 0 {
+		percent := float64(counts.Positive) * 100 / float64(rated)
+		reputation.PositivePercent = &percent
+	}
+	return reputation, nil
+}
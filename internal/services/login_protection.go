@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/mailer"
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// LoginProtection configures how failed logins are throttled. Failures are
+// counted per account and per IP address inside FailureWindow. After
+// FreeAttempts every new failure doubles the wait before the next try, up to
+// MaxDelay, and reaching the max failures locks logins for LockoutDuration.
+type LoginProtection struct {
+	FreeAttempts       int
+	BaseDelay          time.Duration
+	MaxDelay           time.Duration
+	MaxAccountFailures int
+	MaxIPFailures      int
+	FailureWindow      time.Duration
+	LockoutDuration    time.Duration
+}
+
+var DefaultLoginProtection = LoginProtection{
+	FreeAttempts:       3,
+	BaseDelay:          time.Second,
+	MaxDelay:           30 * time.Second,
+	MaxAccountFailures: 10,
+	MaxIPFailures:      50,
+	FailureWindow:      15 * time.Minute,
+	LockoutDuration:    15 * time.Minute,
+}
+
+const (
+	loginScopeAccount = "account"
+	loginScopeIP      = "ip"
+
+	AuditLoginSucceeded = "login_succeeded"
+	AuditLoginFailed    = "login_failed"
+	AuditLoginThrottled = "login_throttled"
+	AuditAccountLocked  = "account_locked"
+	AuditIPLocked       = "ip_locked"
+)
+
+// LoginThrottledError is returned while an account or an IP has to wait
+// before trying to log in again.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "too many failed logins, try again later"
+	}
+	return "too many failed logins, slow down"
+}
+
+func (lp LoginProtection) delay(failures int32) time.Duration {
+	extra := int(failures) - lp.FreeAttempts
+	if extra < 0 {
+		return 0
+	}
+	delay := time.Duration(float64(lp.BaseDelay) * math.Pow(2, float64(extra)))
+	if delay > lp.MaxDelay || delay <= 0 {
+		return lp.MaxDelay
+	}
+	return delay
+}
+
+// checkLoginAllowed returns a LoginThrottledError when the subject is locked
+// or still inside its progressive delay.
+func (us *UserService) checkLoginAllowed(ctx context.Context, scope, subject string) error {
+	failure, err := us.queries.GetLoginFailure(ctx, pgstore.GetLoginFailureParams{Scope: scope, Subject: subject})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	if failure.LockedUntil.Valid && failure.LockedUntil.Time.After(now) {
+		return &LoginThrottledError{RetryAfter: failure.LockedUntil.Time.Sub(now), Locked: true}
+	}
+	if failure.LastFailedAt.Before(now.Add(-us.loginProtection.FailureWindow)) {
+		return nil
+	}
+	if next := failure.LastFailedAt.Add(us.loginProtection.delay(failure.FailedCount)); next.After(now) {
+		return &LoginThrottledError{RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failure for subject and locks it when max is
+// reached. It reports whether the subject got locked by this failure.
+func (us *UserService) recordLoginFailure(ctx context.Context, scope, subject string, max int) (bool, error) {
+	failure, err := us.queries.RecordLoginFailure(ctx, pgstore.RecordLoginFailureParams{
+		Scope:       scope,
+		Subject:     subject,
+		WindowStart: time.Now().Add(-us.loginProtection.FailureWindow),
+	})
+	if err != nil {
+		return false, err
+	}
+	if int(failure.FailedCount) < max {
+		return false, nil
+	}
+	err = us.queries.LockLoginSubject(ctx, pgstore.LockLoginSubjectParams{
+		Scope:       scope,
+		Subject:     subject,
+		LockedUntil: pgtype.Timestamptz{Time: time.Now().Add(us.loginProtection.LockoutDuration), Valid: true},
+	})
+	return err == nil, err
+}
+
+func (us *UserService) audit(ctx context.Context, userId *uuid.UUID, email, ip, event string) {
+	err := us.queries.CreateAuthAuditEntry(ctx, pgstore.CreateAuthAuditEntryParams{
+		UserID:    optionalUUID(userId),
+		Email:     email,
+		IpAddress: ip,
+		Event:     event,
+	})
+	if err != nil {
+		slog.Error("Failed to write auth audit entry", "event", event, "error", err)
+	}
+}
+
+func (us *UserService) notifyLockout(ctx context.Context, user pgstore.GetUserByEmailRow, ip string) {
+	err := us.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your GoBid account was temporarily locked",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe locked logins to your account for %s after too many failed attempts, the last one from %s.\n\nIf it was not you, reset your password at %s/reset-password.\n",
+			user.UserName, us.loginProtection.LockoutDuration, ip, us.appURL,
+		),
+	})
+	if err != nil {
+		slog.Error("Failed to send lockout notification", "user_id", user.ID, "error", err)
+	}
+}
+
+func loginAccountSubject(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
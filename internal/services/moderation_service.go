@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Actions written to the admin audit log.
+const (
+	AuditRoleChanged       = "role_changed"
+	AuditUserSuspended     = "user_suspended"
+	AuditUserUnsuspended   = "user_unsuspended"
+	AuditAuctionCanceled   = "auction_canceled"
+	AuditBidVoided         = "bid_voided"
+	AuditShillFlagReviewed = "shill_flag_reviewed"
+)
+
+var (
+	ErrCannotModerateSelf = errors.New("you cannot change your own role or suspension")
+	ErrCannotModerateRole = errors.New("you cannot moderate a user whose role is equal to or above yours")
+	ErrUserNotSuspended   = errors.New("user is not suspended")
+	ErrUserSuspended      = errors.New("user is already suspended")
+	ErrBidNotFound        = errors.New("bid not found or already voided")
+)
+
+type ModerationService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+}
+
+func NewModerationService(pool *pgxpool.Pool) ModerationService {
+	return ModerationService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+	}
+}
+
+// inTx runs fn in a transaction and writes the audit entry in the same one,
+// an action is never applied without its trace.
+func (ms *ModerationService) inTx(ctx context.Context, fn func(queries *pgstore.Queries) (pgstore.CreateAdminAuditEntryParams, error)) error {
+	tx, err := ms.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := ms.queries.WithTx(tx)
+
+	entry, err := fn(queries)
+	if err != nil {
+		return err
+	}
+	if err := queries.CreateAdminAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (ms *ModerationService) ChangeRole(ctx context.Context, actorId, userId uuid.UUID, role Role) error {
+	if actorId == userId {
+		return ErrCannotModerateSelf
+	}
+	return ms.changeRole(ctx, optionalUUID(&actorId), userId, role)
+}
+
+// BootstrapAdmins gives the admin role to the given users at startup, so a
+// fresh database has someone able to hand out roles. Once there is an admin
+// it does nothing, demoted admins are not promoted again on restart.
+func (ms *ModerationService) BootstrapAdmins(ctx context.Context, userIds []uuid.UUID) error {
+	admins, err := ms.queries.CountAdmins(ctx)
+	if err != nil {
+		return err
+	}
+	if admins > 0 {
+		return nil
+	}
+	for _, id := range userIds {
+		authz, err := getAuthz(ctx, ms.queries, id)
+		if err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				continue
+			}
+			return err
+		}
+		if authz.Role == RoleAdmin {
+			continue
+		}
+		if err := ms.changeRole(ctx, pgtype.UUID{}, id, RoleAdmin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ms *ModerationService) changeRole(ctx context.Context, actorId pgtype.UUID, userId uuid.UUID, role Role) error {
+	return ms.inTx(ctx, func(queries *pgstore.Queries) (pgstore.CreateAdminAuditEntryParams, error) {
+		previous, err := queries.UpdateUserRole(ctx, pgstore.UpdateUserRoleParams{Role: string(role), ID: userId})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgstore.CreateAdminAuditEntryParams{}, ErrUserNotFound
+			}
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		return pgstore.CreateAdminAuditEntryParams{
+			ActorID:  actorId,
+			Action:   AuditRoleChanged,
+			TargetID: userId,
+			Details:  fmt.Sprintf("%s -> %s", previous, role),
+		}, nil
+	})
+}
+
+func (ms *ModerationService) SuspendUser(ctx context.Context, actorId, userId uuid.UUID, reason string) error {
+	if actorId == userId {
+		return ErrCannotModerateSelf
+	}
+	return ms.inTx(ctx, func(queries *pgstore.Queries) (pgstore.CreateAdminAuditEntryParams, error) {
+		if err := requireOutranks(ctx, queries, actorId, userId); err != nil {
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		suspended, err := queries.SuspendUser(ctx, pgstore.SuspendUserParams{
+			ID:               userId,
+			SuspensionReason: pgtype.Text{String: reason, Valid: true},
+		})
+		if err != nil {
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		if suspended == 0 {
+			return pgstore.CreateAdminAuditEntryParams{}, ErrUserSuspended
+		}
+		return pgstore.CreateAdminAuditEntryParams{
+			ActorID:  optionalUUID(&actorId),
+			Action:   AuditUserSuspended,
+			TargetID: userId,
+			Details:  reason,
+		}, nil
+	})
+}
+
+func (ms *ModerationService) UnsuspendUser(ctx context.Context, actorId, userId uuid.UUID) error {
+	if actorId == userId {
+		return ErrCannotModerateSelf
+	}
+	return ms.inTx(ctx, func(queries *pgstore.Queries) (pgstore.CreateAdminAuditEntryParams, error) {
+		if err := requireOutranks(ctx, queries, actorId, userId); err != nil {
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		unsuspended, err := queries.UnsuspendUser(ctx, userId)
+		if err != nil {
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		if unsuspended == 0 {
+			return pgstore.CreateAdminAuditEntryParams{}, ErrUserNotSuspended
+		}
+		return pgstore.CreateAdminAuditEntryParams{
+			ActorID:  optionalUUID(&actorId),
+			Action:   AuditUserUnsuspended,
+			TargetID: userId,
+		}, nil
+	})
+}
+
+// requireOutranks checks that the actor's role is above the one of the user
+// they suspend or unsuspend.
+func requireOutranks(ctx context.Context, queries *pgstore.Queries, actorId, userId uuid.UUID) error {
+	actor, err := getAuthz(ctx, queries, actorId)
+	if err != nil {
+		return err
+	}
+	target, err := getAuthz(ctx, queries, userId)
+	if err != nil {
+		return err
+	}
+	if !actor.Role.Outranks(target.Role) {
+		return ErrCannotModerateRole
+	}
+	return nil
+}
+
+// CancelAuction withdraws an open auction on behalf of the platform, unlike
+// the seller withdrawal it does not care about bids.
+func (ms *ModerationService) CancelAuction(ctx context.Context, actorId, productId uuid.UUID, reason string) error {
+	return ms.inTx(ctx, func(queries *pgstore.Queries) (pgstore.CreateAdminAuditEntryParams, error) {
+		if _, err := lockOpenProduct(ctx, queries, productId); err != nil {
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		if _, err := queries.WithdrawProduct(ctx, pgstore.WithdrawProductParams{
+			ID:               productId,
+			WithdrawalReason: pgtype.Text{String: reason, Valid: true},
+		}); err != nil {
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		return pgstore.CreateAdminAuditEntryParams{
+			ActorID:  optionalUUID(&actorId),
+			Action:   AuditAuctionCanceled,
+			TargetID: productId,
+			Details:  reason,
+		}, nil
+	})
+}
+
+// VoidBid removes a bid from the auction, it no longer counts for the price
+// or the history. Bids of ended or settled auctions are final. It returns the
+// voided bid.
+func (ms *ModerationService) VoidBid(ctx context.Context, actorId, bidId uuid.UUID, reason string) (pgstore.Bid, error) {
+	var bid pgstore.Bid
+	err := ms.inTx(ctx, func(queries *pgstore.Queries) (pgstore.CreateAdminAuditEntryParams, error) {
+		found, err := queries.GetBidById(ctx, bidId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgstore.CreateAdminAuditEntryParams{}, ErrBidNotFound
+			}
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		if _, err := lockOpenProduct(ctx, queries, found.ProductID); err != nil {
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		bid, err = queries.VoidBid(ctx, pgstore.VoidBidParams{
+			ID:         bidId,
+			VoidReason: pgtype.Text{String: reason, Valid: true},
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgstore.CreateAdminAuditEntryParams{}, ErrBidNotFound
+			}
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		return pgstore.CreateAdminAuditEntryParams{
+			ActorID:  optionalUUID(&actorId),
+			Action:   AuditBidVoided,
+			TargetID: bidId,
+			Details:  fmt.Sprintf("product %s, amount %.2f: %s", bid.ProductID, bid.BidAmount, reason),
+		}, nil
+	})
+	return bid, err
+}
+
+func (ms *ModerationService) ListAuditLog(ctx context.Context, targetId *uuid.UUID, limit int32) ([]pgstore.AdminAuditLog, error) {
+	entries, err := ms.queries.ListAdminAuditEntries(ctx, pgstore.ListAdminAuditEntriesParams{
+		TargetID: optionalUUID(targetId),
+		PageSize: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []pgstore.AdminAuditLog{}
+	}
+	return entries, nil
+}
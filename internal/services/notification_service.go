@@ -0,0 +1,376 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotificationKind string
+
+const (
+	NotificationOutbid        NotificationKind = "outbid"
+	NotificationAuctionWon    NotificationKind = "auction_won"
+	NotificationAuctionLost   NotificationKind = "auction_lost"
+	NotificationAuctionEnding NotificationKind = "auction_ending"
+	NotificationPaymentDue    NotificationKind = "payment_due"
+	NotificationSaleSettled   NotificationKind = "sale_settled"
+	NotificationSearchMatch   NotificationKind = "saved_search_match"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+const (
+	notifyTimeout = 30 * time.Second
+	// DefaultAuctionEndInterval is how often ended auctions that were not
+	// announced yet are looked for.
+	DefaultAuctionEndInterval = time.Minute
+	auctionEndBatchSize       = 100
+)
+
+type Notification struct {
+	ID        uuid.UUID        `json:"id"`
+	Kind      NotificationKind `json:"kind"`
+	ProductID *uuid.UUID       `json:"product_id"`
+	Title     string           `json:"title"`
+	Body      string           `json:"body"`
+	ReadAt    *time.Time       `json:"read_at"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int64          `json:"unread_count"`
+	NextCursor    string         `json:"next_cursor,omitempty"`
+}
+
+func notification(row pgstore.Notification) Notification {
+	return Notification{
+		ID:        row.ID,
+		Kind:      NotificationKind(row.Kind),
+		ProductID: nullableUUID(row.ProductID),
+		Title:     row.Title,
+		Body:      row.Body,
+		ReadAt:    nullableTime(row.ReadAt),
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// NotificationService stores the notifications of every user and pushes
+// them to the streams the user has open. Copies share the same streams.
+type NotificationService struct {
+	pool    *pgxpool.Pool
+	queries *pgstore.Queries
+	streams *notificationStreams
+}
+
+func NewNotificationService(pool *pgxpool.Pool) NotificationService {
+	return NotificationService{
+		pool:    pool,
+		queries: pgstore.New(pool),
+		streams: &notificationStreams{subscribers: make(map[uuid.UUID]map[*notificationSubscriber]struct{})},
+	}
+}
+
+type notificationStreams struct {
+	sync.Mutex
+	subscribers map[uuid.UUID]map[*notificationSubscriber]struct{}
+}
+
+type notificationSubscriber struct {
+	notifications chan Notification
+	// sessionId is the session the stream was opened with, uuid.Nil when it
+	// used a personal access token.
+	sessionId uuid.UUID
+	// closed is closed with the reason set when the stream must end.
+	closed    chan struct{}
+	reason    string
+	closeOnce sync.Once
+}
+
+func (sub *notificationSubscriber) close(reason string) {
+	sub.closeOnce.Do(func() {
+		sub.reason = reason
+		close(sub.closed)
+	})
+}
+
+// subscribe returns the subscriber receiving the notifications of userId
+// and the function to call once the stream is closed.
+func (s *notificationStreams) subscribe(userId, sessionId uuid.UUID) (*notificationSubscriber, func()) {
+	sub := &notificationSubscriber{
+		notifications: make(chan Notification, 64),
+		sessionId:     sessionId,
+		closed:        make(chan struct{}),
+	}
+	s.Lock()
+	if s.subscribers[userId] == nil {
+		s.subscribers[userId] = make(map[*notificationSubscriber]struct{})
+	}
+	s.subscribers[userId][sub] = struct{}{}
+	s.Unlock()
+
+	return sub, func() {
+		s.Lock()
+		delete(s.subscribers[userId], sub)
+		if len(s.subscribers[userId]) == 0 {
+			delete(s.subscribers, userId)
+		}
+		s.Unlock()
+	}
+}
+
+// publish never blocks, a stream that is too slow to keep up misses the
+// notification, it is still listed by the API.
+func (s *notificationStreams) publish(userId uuid.UUID, n Notification) {
+	s.Lock()
+	defer s.Unlock()
+	for sub := range s.subscribers[userId] {
+		select {
+		case sub.notifications <- n:
+		default:
+		}
+	}
+}
+
+// CloseStreams ends the notification streams of userId whose session
+// matches, like AuctionRoom.DisconnectClients does for the auction sockets.
+func (ns *NotificationService) CloseStreams(userId uuid.UUID, match func(sessionId uuid.UUID) bool, reason string) {
+	ns.streams.Lock()
+	defer ns.streams.Unlock()
+	for sub := range ns.streams.subscribers[userId] {
+		if match(sub.sessionId) {
+			sub.close(reason)
+		}
+	}
+}
+
+// Notify stores a notification for userId and pushes it to their open
+// streams. productId is nil for notifications about no auction in particular.
+func (ns *NotificationService) Notify(ctx context.Context, userId uuid.UUID, kind NotificationKind, productId *uuid.UUID, title, body string) error {
+	row, err := ns.queries.CreateNotification(ctx, pgstore.CreateNotificationParams{
+		UserID:    userId,
+		Kind:      string(kind),
+		ProductID: optionalUUID(productId),
+		Title:     title,
+		Body:      body,
+	})
+	if err != nil {
+		return err
+	}
+	ns.streams.publish(userId, notification(row))
+	return nil
+}
+
+// ListNotifications returns the notifications of userId, newest first, with
+// the number of unread ones.
+func (ns *NotificationService) ListNotifications(ctx context.Context, userId uuid.UUID, unreadOnly bool, cursor string, limit int32) (NotificationPage, error) {
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return NotificationPage{}, err
+	}
+
+	params := pgstore.ListNotificationsParams{
+		UserID:     userId,
+		UnreadOnly: unreadOnly,
+		PageSize:   limit + 1,
+	}
+	params.CursorCreatedAt, params.CursorID = after.params()
+	rows, err := ns.queries.ListNotifications(ctx, params)
+	if err != nil {
+		return NotificationPage{}, err
+	}
+	unread, err := ns.queries.CountUnreadNotifications(ctx, userId)
+	if err != nil {
+		return NotificationPage{}, err
+	}
+
+	page := NotificationPage{Notifications: make([]Notification, 0, len(rows)), UnreadCount: unread}
+	for i, row := range rows {
+		if int32(i) == limit {
+			last := rows[i-1]
+			page.NextCursor = encodeKeysetCursor(last.ID, last.CreatedAt)
+			break
+		}
+		page.Notifications = append(page.Notifications, notification(row))
+	}
+	return page, nil
+}
+
+func (ns *NotificationService) CountUnread(ctx context.Context, userId uuid.UUID) (int64, error) {
+	return ns.queries.CountUnreadNotifications(ctx, userId)
+}
+
+// MarkRead marks a notification of userId as read, marking it again keeps
+// the first read time.
+func (ns *NotificationService) MarkRead(ctx context.Context, userId, notificationId uuid.UUID) error {
+	updated, err := ns.queries.MarkNotificationRead(ctx, pgstore.MarkNotificationReadParams{ID: notificationId, UserID: userId})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead returns how many notifications were unread.
+func (ns *NotificationService) MarkAllRead(ctx context.Context, userId uuid.UUID) (int64, error) {
+	return ns.queries.MarkAllNotificationsRead(ctx, userId)
+}
+
+// NotifyOutbid tells the previous highest bidder that bid took the lead. It
+// is called by the auction room after every accepted bid.
+func (ns *NotificationService) NotifyOutbid(bid pgstore.Bid) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	outbid, err := ns.queries.GetOutbidBidder(ctx, pgstore.GetOutbidBidderParams{
+		BidID:     bid.ID,
+		ProductID: bid.ProductID,
+		BidderID:  bid.BidderID,
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("Failed to find the outbid bidder", "bid_id", bid.ID, "error", err)
+		}
+		return
+	}
+	err = ns.Notify(ctx, outbid.BidderID, NotificationOutbid, &bid.ProductID,
+		fmt.Sprintf("You were outbid on %s", outbid.ProductName),
+		fmt.Sprintf("Someone bid %.2f, bid again to take the lead.", bid.BidAmount),
+	)
+	if err != nil {
+		slog.Error("Failed to notify outbid bidder", "user_id", outbid.BidderID, "bid_id", bid.ID, "error", err)
+	}
+}
+
+// NotifyAuctionEnded tells the winner they won and owe the payment, and the
+// other bidders that they lost. It is called by the auction room when the
+// auction ends, RunAuctionEndNotifications catches the auctions no room
+// announced. Each auction is announced once.
+func (ns *NotificationService) NotifyAuctionEnded(productId uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	if err := ns.announceEndedAuction(ctx, productId); err != nil {
+		slog.Error("Failed to notify auction bidders", "product_id", productId, "error", err)
+	}
+}
+
+// NotifyEndedAuctions announces the ended auctions that were not announced
+// yet, at most limit of them.
+func (ns *NotificationService) NotifyEndedAuctions(ctx context.Context, limit int32) error {
+	ids, err := ns.queries.ListUnannouncedEndedAuctions(ctx, limit)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := ns.announceEndedAuction(ctx, id); err != nil {
+			slog.Error("Failed to notify auction bidders", "product_id", id, "error", err)
+		}
+	}
+	return nil
+}
+
+// RunAuctionEndNotifications calls NotifyEndedAuctions every interval until
+// ctx is done, so auctions that ended while no room was running, or whose
+// room failed to notify, are still announced.
+func (ns *NotificationService) RunAuctionEndNotifications(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ns.NotifyEndedAuctions(ctx, auctionEndBatchSize); err != nil {
+				slog.Error("Failed to notify ended auctions", "error", err)
+			}
+		}
+	}
+}
+
+// announceEndedAuction stores the won, lost and payment due notifications
+// of an ended auction in one transaction and pushes them to the streams
+// once committed. An auction already announced is skipped.
+func (ns *NotificationService) announceEndedAuction(ctx context.Context, productId uuid.UUID) error {
+	tx, err := ns.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := ns.queries.WithTx(tx)
+
+	if _, err := queries.ClaimEndedAuction(ctx, productId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	product, err := queries.GetProductById(ctx, productId)
+	if err != nil {
+		return err
+	}
+	bidders, err := queries.ListAuctionBidders(ctx, productId)
+	if err != nil {
+		return err
+	}
+
+	var created []pgstore.Notification
+	add := func(userId uuid.UUID, kind NotificationKind, title, body string) error {
+		row, err := queries.CreateAuctionEndNotification(ctx, pgstore.CreateAuctionEndNotificationParams{
+			UserID:    userId,
+			Kind:      string(kind),
+			ProductID: optionalUUID(&productId),
+			Title:     title,
+			Body:      body,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		created = append(created, row)
+		return nil
+	}
+	for i, b := range bidders {
+		if i == 0 {
+			err = add(b.BidderID, NotificationAuctionWon,
+				fmt.Sprintf("You won %s", product.ProductName),
+				fmt.Sprintf("Your bid of %.2f won the auction.", b.TopBid),
+			)
+			if err == nil {
+				err = add(b.BidderID, NotificationPaymentDue,
+					fmt.Sprintf("Payment due for %s", product.ProductName),
+					fmt.Sprintf("Pay %.2f to the seller, the sale is settled once they receive it.", b.TopBid),
+				)
+			}
+		} else {
+			err = add(b.BidderID, NotificationAuctionLost,
+				fmt.Sprintf("You did not win %s", product.ProductName),
+				fmt.Sprintf("The auction ended, your best bid was %.2f.", b.TopBid),
+			)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	for _, row := range created {
+		ns.streams.publish(row.UserID, notification(row))
+	}
+	return nil
+}
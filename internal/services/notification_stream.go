@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// NotificationEvent is what the notification stream writes. The first event
+// only carries the unread count, the next ones the new notification too.
+type NotificationEvent struct {
+	Notification *Notification `json:"notification,omitempty"`
+	UnreadCount  int64         `json:"unread_count"`
+}
+
+// Stream writes the notifications of userId to conn as they are created,
+// until the client goes away or CloseStreams ends it. sessionId is the
+// session the stream was opened with. Anything the client sends is ignored.
+func (ns *NotificationService) Stream(conn *websocket.Conn, userId, sessionId uuid.UUID) {
+	sub, unsubscribe := ns.streams.subscribe(userId, sessionId)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(maxMessageSize)
+		conn.SetReadDeadline(time.Now().Add(readDeadline))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(readDeadline))
+			return nil
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	send := func(n *Notification) bool {
+		ctx, cancel := context.WithTimeout(context.Background(), writeWait)
+		defer cancel()
+		unread, err := ns.CountUnread(ctx, userId)
+		if err != nil {
+			slog.Error("Failed to count unread notifications", "user_id", userId, "error", err)
+			return false
+		}
+		conn.SetWriteDeadline(time.Now().Add(writeWait))
+		return conn.WriteJSON(NotificationEvent{Notification: n, UnreadCount: unread}) == nil
+	}
+
+	if !send(nil) {
+		return
+	}
+	for {
+		select {
+		case <-closed:
+			return
+		case <-sub.closed:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, sub.reason))
+			return
+		case n := <-sub.notifications:
+			if !send(&n) {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
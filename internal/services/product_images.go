@@ -0,0 +1,256 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/image/draw"
+)
+
+const (
+	MaxImageSize        = 5 << 20
+	MaxImagesPerProduct = 10
+	thumbnailMaxSize    = 320
+	maxImageDimension   = 8000
+	// maxImagePixels bounds the memory used to decode an upload, a small
+	// compressed file can describe a huge image.
+	maxImagePixels       = 16_000_000
+	jpegEncodingQuality  = 85
+	productImagesKeyBase = "products"
+)
+
+var (
+	ErrImageTooLarge        = errors.New("image is too large")
+	ErrUnsupportedImage     = errors.New("unsupported image type, use jpeg, png or gif")
+	ErrTooManyImages        = errors.New("product already has the maximum number of images")
+	ErrProductImageNotFound = errors.New("product image not found")
+)
+
+type ProductImage struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+}
+
+// processedImage is an upload decoded and encoded again. Re-encoding drops
+// every metadata block of the original file, EXIF included.
+type processedImage struct {
+	contentType string
+	extension   string
+	data        []byte
+	thumbnail   []byte
+	width       int
+	height      int
+}
+
+func processImage(r io.Reader) (processedImage, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, MaxImageSize+1))
+	if err != nil {
+		return processedImage{}, err
+	}
+	if len(raw) > MaxImageSize {
+		return processedImage{}, ErrImageTooLarge
+	}
+
+	contentType := http.DetectContentType(raw)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return processedImage{}, ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return processedImage{}, ErrUnsupportedImage
+	}
+	if config.Width > maxImageDimension || config.Height > maxImageDimension ||
+		config.Width*config.Height > maxImagePixels {
+		return processedImage{}, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return processedImage{}, ErrUnsupportedImage
+	}
+
+	// Gifs are stored as png, only the first frame is kept.
+	encode := func(w io.Writer, img image.Image) error { return png.Encode(w, img) }
+	out := processedImage{contentType: "image/png", extension: "png"}
+	if contentType == "image/jpeg" {
+		encode = func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegEncodingQuality})
+		}
+		out = processedImage{contentType: "image/jpeg", extension: "jpg"}
+	}
+
+	var data bytes.Buffer
+	if err := encode(&data, img); err != nil {
+		return processedImage{}, err
+	}
+	var thumbnail bytes.Buffer
+	if err := encode(&thumbnail, resizeToFit(img, thumbnailMaxSize)); err != nil {
+		return processedImage{}, err
+	}
+
+	out.data = data.Bytes()
+	out.thumbnail = thumbnail.Bytes()
+	out.width = img.Bounds().Dx()
+	out.height = img.Bounds().Dy()
+	return out, nil
+}
+
+// resizeToFit scales img down so its biggest side is at most size pixels,
+// keeping the aspect ratio. Smaller images are returned untouched.
+func resizeToFit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+
+	if width >= height {
+		height = max(height*size/width, 1)
+		width = size
+	} else {
+		width = max(width*size/height, 1)
+		height = size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// AddProductImage stores an image of an open auction. The product row stays
+// locked from the image count to the insert, so concurrent uploads cannot go
+// over MaxImagesPerProduct.
+func (ps *ProductService) AddProductImage(ctx context.Context, productId uuid.UUID, r io.Reader) (ProductImage, error) {
+	processed, err := processImage(r)
+	if err != nil {
+		return ProductImage{}, err
+	}
+
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return ProductImage{}, err
+	}
+	defer tx.Rollback(ctx)
+	queries := ps.queries.WithTx(tx)
+
+	if _, err := lockOpenProduct(ctx, queries, productId); err != nil {
+		return ProductImage{}, err
+	}
+	count, err := queries.CountProductImages(ctx, productId)
+	if err != nil {
+		return ProductImage{}, err
+	}
+	if count >= MaxImagesPerProduct {
+		return ProductImage{}, ErrTooManyImages
+	}
+
+	id := uuid.New()
+	key := fmt.Sprintf("%s/%s/%s.%s", productImagesKeyBase, productId, id, processed.extension)
+	thumbnailKey := fmt.Sprintf("%s/%s/%s_thumb.%s", productImagesKeyBase, productId, id, processed.extension)
+
+	if err := ps.storage.Put(ctx, key, bytes.NewReader(processed.data), processed.contentType); err != nil {
+		return ProductImage{}, err
+	}
+	if err := ps.storage.Put(ctx, thumbnailKey, bytes.NewReader(processed.thumbnail), processed.contentType); err != nil {
+		ps.deleteStoredImage(ctx, key)
+		return ProductImage{}, err
+	}
+
+	created, err := queries.CreateProductImage(ctx, pgstore.CreateProductImageParams{
+		ID:           id,
+		ProductID:    productId,
+		StorageKey:   key,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  processed.contentType,
+		Width:        int32(processed.width),
+		Height:       int32(processed.height),
+	})
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		ps.deleteStoredImage(ctx, key)
+		ps.deleteStoredImage(ctx, thumbnailKey)
+		return ProductImage{}, err
+	}
+	return ps.productImage(created), nil
+}
+
+func (ps *ProductService) DeleteProductImage(ctx context.Context, productId, imageId uuid.UUID) error {
+	deleted, err := ps.queries.DeleteProductImage(ctx, pgstore.DeleteProductImageParams{
+		ID:        imageId,
+		ProductID: productId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductImageNotFound
+		}
+		return err
+	}
+	ps.deleteStoredImage(ctx, deleted.StorageKey)
+	ps.deleteStoredImage(ctx, deleted.ThumbnailKey)
+	return nil
+}
+
+func (ps *ProductService) GetProductImages(ctx context.Context, productId uuid.UUID) ([]ProductImage, error) {
+	rows, err := ps.queries.GetProductImagesByProductId(ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+	images := make([]ProductImage, 0, len(rows))
+	for _, row := range rows {
+		images = append(images, ps.productImage(row))
+	}
+	return images, nil
+}
+
+// getImagesOfProducts loads the images of many products at once, keyed by
+// product id.
+func (ps *ProductService) getImagesOfProducts(ctx context.Context, productIds []uuid.UUID) (map[uuid.UUID][]ProductImage, error) {
+	images := make(map[uuid.UUID][]ProductImage, len(productIds))
+	if len(productIds) == 0 {
+		return images, nil
+	}
+	rows, err := ps.queries.GetProductImagesByProductIds(ctx, productIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		images[row.ProductID] = append(images[row.ProductID], ps.productImage(row))
+	}
+	return images, nil
+}
+
+func (ps *ProductService) productImage(row pgstore.ProductImage) ProductImage {
+	return ProductImage{
+		ID:           row.ID,
+		URL:          ps.storage.URL(row.StorageKey),
+		ThumbnailURL: ps.storage.URL(row.ThumbnailKey),
+		Width:        row.Width,
+		Height:       row.Height,
+	}
+}
+
+func (ps *ProductService) deleteStoredImage(ctx context.Context, key string) {
+	if err := ps.storage.Delete(ctx, key); err != nil {
+		slog.Error("Failed to delete stored image", "key", key, "error", err)
+	}
+}
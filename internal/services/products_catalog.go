@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ProductSort string
+
+const (
+	SortEndingSoonest ProductSort = "ending_soonest"
+	SortNewest        ProductSort = "newest"
+	SortHighestBid    ProductSort = "highest_bid"
+	SortMostBids      ProductSort = "most_bids"
+)
+
+type ProductStatus string
+
+const (
+	StatusActive ProductStatus = "active"
+	StatusEnded  ProductStatus = "ended"
+	StatusSold   ProductStatus = "sold"
+)
+
+type ProductFilter struct {
+	Status       ProductStatus
+	SellerId     *uuid.UUID
+	MinPrice     *float64
+	MaxPrice     *float64
+	EndingAfter  *time.Time
+	EndingBefore *time.Time
+	CategoryId   *uuid.UUID
+	// ViewerId is who is browsing, nil for anonymous requests. Invite only
+	// auctions are only listed for their seller and invited users.
+	ViewerId *uuid.UUID
+}
+
+type CatalogProduct struct {
+	ID           uuid.UUID      `json:"id"`
+	SellerID     uuid.UUID      `json:"seller_id"`
+	ProductName  string         `json:"product_name"`
+	Description  string         `json:"description"`
+	BasePrice    float64        `json:"base_price"`
+	CurrentPrice float64        `json:"current_price"`
+	HighestBid   float64        `json:"highest_bid"`
+	BidCount     int64          `json:"bid_count"`
+	CategoryID   *uuid.UUID     `json:"category_id,omitempty"`
+	Tags         []string       `json:"tags"`
+	Images       []ProductImage `json:"images"`
+	AuctionEnd   time.Time      `json:"auction_end"`
+	IsSold       bool           `json:"is_sold"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type CatalogPage struct {
+	Products   []CatalogProduct `json:"products"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// catalogCursor is the position of the last product of a page. Only the field
+// matching Sort is set besides Id.
+type catalogCursor struct {
+	Sort       ProductSort `json:"s"`
+	Id         uuid.UUID   `json:"id"`
+	AuctionEnd time.Time   `json:"ae,omitempty"`
+	CreatedAt  time.Time   `json:"ca,omitempty"`
+	HighestBid float64     `json:"hb,omitempty"`
+	BidCount   int64       `json:"bc,omitempty"`
+	Rank       float64     `json:"r,omitempty"`
+}
+
+func decodeCatalogCursor(s string, sort ProductSort) (*catalogCursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var c catalogCursor
+	if err := decodeCursor(s, &c); err != nil {
+		return nil, err
+	}
+	if c.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+func (ps *ProductService) ListProducts(ctx context.Context, filter ProductFilter, sort ProductSort, cursor string, limit int32) (CatalogPage, error) {
+	after, err := decodeCatalogCursor(cursor, sort)
+	if err != nil {
+		return CatalogPage{}, err
+	}
+
+	status := pgtype.Text{String: string(filter.Status), Valid: filter.Status != ""}
+	sellerId := optionalUUID(filter.SellerId)
+	minPrice := optionalFloat(filter.MinPrice)
+	maxPrice := optionalFloat(filter.MaxPrice)
+	endingAfter := optionalTime(filter.EndingAfter)
+	endingBefore := optionalTime(filter.EndingBefore)
+	categoryId := optionalUUID(filter.CategoryId)
+	viewerId := optionalUUID(filter.ViewerId)
+
+	var cursorId pgtype.UUID
+	if after != nil {
+		cursorId = pgtype.UUID{Bytes: after.Id, Valid: true}
+	}
+
+	// Fetch one extra row to know whether there is a next page.
+	pageSize := limit + 1
+
+	var rows []pgstore.ListProductsEndingSoonestRow
+	switch sort {
+	case SortNewest:
+		var cursorCreatedAt pgtype.Timestamptz
+		if after != nil {
+			cursorCreatedAt = pgtype.Timestamptz{Time: after.CreatedAt, Valid: true}
+		}
+		newest, err := ps.queries.ListProductsNewest(ctx, pgstore.ListProductsNewestParams{
+			Status:          status,
+			ViewerID:        viewerId,
+			SellerID:        sellerId,
+			MinPrice:        minPrice,
+			MaxPrice:        maxPrice,
+			EndingAfter:     endingAfter,
+			EndingBefore:    endingBefore,
+			CursorID:        cursorId,
+			CategoryID:      categoryId,
+			CursorCreatedAt: cursorCreatedAt,
+			PageSize:        pageSize,
+		})
+		if err != nil {
+			return CatalogPage{}, err
+		}
+		for _, r := range newest {
+			rows = append(rows, pgstore.ListProductsEndingSoonestRow(r))
+		}
+	case SortHighestBid:
+		var cursorHighestBid pgtype.Float8
+		if after != nil {
+			cursorHighestBid = pgtype.Float8{Float64: after.HighestBid, Valid: true}
+		}
+		highest, err := ps.queries.ListProductsHighestBid(ctx, pgstore.ListProductsHighestBidParams{
+			Status:           status,
+			ViewerID:         viewerId,
+			SellerID:         sellerId,
+			MinPrice:         minPrice,
+			MaxPrice:         maxPrice,
+			EndingAfter:      endingAfter,
+			EndingBefore:     endingBefore,
+			CursorID:         cursorId,
+			CategoryID:       categoryId,
+			CursorHighestBid: cursorHighestBid,
+			PageSize:         pageSize,
+		})
+		if err != nil {
+			return CatalogPage{}, err
+		}
+		for _, r := range highest {
+			rows = append(rows, pgstore.ListProductsEndingSoonestRow(r))
+		}
+	case SortMostBids:
+		var cursorBidCount pgtype.Int8
+		if after != nil {
+			cursorBidCount = pgtype.Int8{Int64: after.BidCount, Valid: true}
+		}
+		most, err := ps.queries.ListProductsMostBids(ctx, pgstore.ListProductsMostBidsParams{
+			Status:         status,
+			ViewerID:       viewerId,
+			SellerID:       sellerId,
+			MinPrice:       minPrice,
+			MaxPrice:       maxPrice,
+			EndingAfter:    endingAfter,
+			EndingBefore:   endingBefore,
+			CursorID:       cursorId,
+			CategoryID:     categoryId,
+			CursorBidCount: cursorBidCount,
+			PageSize:       pageSize,
+		})
+		if err != nil {
+			return CatalogPage{}, err
+		}
+		for _, r := range most {
+			rows = append(rows, pgstore.ListProductsEndingSoonestRow(r))
+		}
+	default:
+		var cursorAuctionEnd pgtype.Timestamptz
+		if after != nil {
+			cursorAuctionEnd = pgtype.Timestamptz{Time: after.AuctionEnd, Valid: true}
+		}
+		rows, err = ps.queries.ListProductsEndingSoonest(ctx, pgstore.ListProductsEndingSoonestParams{
+			Status:           status,
+			ViewerID:         viewerId,
+			SellerID:         sellerId,
+			MinPrice:         minPrice,
+			MaxPrice:         maxPrice,
+			EndingAfter:      endingAfter,
+			EndingBefore:     endingBefore,
+			CursorID:         cursorId,
+			CategoryID:       categoryId,
+			CursorAuctionEnd: cursorAuctionEnd,
+			PageSize:         pageSize,
+		})
+		if err != nil {
+			return CatalogPage{}, err
+		}
+	}
+
+	page := CatalogPage{Products: make([]CatalogProduct, 0, len(rows))}
+	for i, r := range rows {
+		if int32(i) == limit {
+			last := rows[i-1]
+			page.NextCursor = encodeCursor(catalogCursor{
+				Sort:       sort,
+				Id:         last.ID,
+				AuctionEnd: last.AuctionEnd,
+				CreatedAt:  last.CreatedAt,
+				HighestBid: last.HighestBid,
+				BidCount:   last.BidCount,
+			})
+			break
+		}
+		page.Products = append(page.Products, CatalogProduct{
+			ID:           r.ID,
+			SellerID:     r.SellerID,
+			ProductName:  r.ProductName,
+			Description:  r.Description,
+			BasePrice:    r.BasePrice,
+			CurrentPrice: max(r.BasePrice, r.HighestBid),
+			HighestBid:   r.HighestBid,
+			BidCount:     r.BidCount,
+			CategoryID:   nullableUUID(r.CategoryID),
+			Tags:         r.Tags,
+			AuctionEnd:   r.AuctionEnd,
+			IsSold:       r.IsSold,
+			CreatedAt:    r.CreatedAt,
+		})
+	}
+
+	if err := ps.attachImages(ctx, page.Products); err != nil {
+		return CatalogPage{}, err
+	}
+	return page, nil
+}
+
+func (ps *ProductService) attachImages(ctx context.Context, products []CatalogProduct) error {
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	images, err := ps.getImagesOfProducts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].Images = images[products[i].ID]
+		if products[i].Images == nil {
+			products[i].Images = []ProductImage{}
+		}
+	}
+	return nil
+}
+
+func optionalUUID(v *uuid.UUID) pgtype.UUID {
+	if v == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *v, Valid: true}
+}
+
+func optionalFloat(v *float64) pgtype.Float8 {
+	if v == nil {
+		return pgtype.Float8{}
+	}
+	return pgtype.Float8{Float64: *v, Valid: true}
+}
+
+func optionalTime(v *time.Time) pgtype.Timestamptz {
+	if v == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *v, Valid: true}
+}
+
+func nullableUUID(v pgtype.UUID) *uuid.UUID {
+	if !v.Valid {
+		return nil
+	}
+	id := uuid.UUID(v.Bytes)
+	return &id
+}
+
+func nullableTime(v pgtype.Timestamptz) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Time
+}
+
+func nullableText(v pgtype.Text) *string {
+	if !v.Valid {
+		return nil
+	}
+	return &v.String
+}
+
+func nullableInt4(v pgtype.Int4) *int32 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int32
+}
+
+func optionalInt4(v *int32) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *v, Valid: true}
+}
+
+func nullableFloat(v pgtype.Float8) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
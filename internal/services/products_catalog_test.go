@@ -0,0 +1,51 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCatalogCursor(t *testing.T) {
+	want := catalogCursor{
+		Sort:       SortHighestBid,
+		Id:         uuid.New(),
+		AuctionEnd: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		HighestBid: 125.5,
+	}
+	encoded := encodeCursor(want)
+
+	tests := []struct {
+		name    string
+		cursor  string
+		sort    ProductSort
+		want    *catalogCursor
+		wantErr error
+	}{
+		{"empty", "", SortHighestBid, nil, nil},
+		{"round trip", encoded, SortHighestBid, &want, nil},
+		{"other sort", encoded, SortNewest, nil, ErrInvalidCursor},
+		{"not base64", "not a cursor!", SortHighestBid, nil, ErrInvalidCursor},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("[1,2]")), SortHighestBid, nil, ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCatalogCursor(tt.cursor, tt.sort)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("got %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || *got != *tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrProductWithdrawn        = errors.New("the auction was withdrawn by the seller")
+	ErrAuctionEnded            = errors.New("the auction has ended")
+	ErrProductHasBids          = errors.New("only the description can be changed after the first bid")
+	ErrDescriptionRewrite      = errors.New("after the first bid the description can only be extended with clarifications")
+	ErrWithdrawalReasonMissing = errors.New("a reason is required to withdraw an auction that has bids")
+)
+
+// ProductChanges holds the fields a seller wants to change, nil fields are
+// kept as they are.
+type ProductChanges struct {
+	ProductName *string
+	Description *string
+	BasePrice   *float64
+	AuctionEnd  *time.Time
+	CategoryId  *uuid.UUID
+}
+
+// EditProduct applies the seller changes to an open auction. Before the first
+// bid anything can change, after it the description can only be extended so
+// bidders are never misled about what they bid on.
+func (ps *ProductService) EditProduct(ctx context.Context, productId uuid.UUID, changes ProductChanges) (pgstore.Product, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	defer tx.Rollback(ctx)
+	queries := ps.queries.WithTx(tx)
+
+	product, err := lockOpenProduct(ctx, queries, productId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	bids, err := queries.CountBidsByProductId(ctx, productId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	if bids > 0 {
+		if changes.ProductName != nil || changes.BasePrice != nil || changes.AuctionEnd != nil || changes.CategoryId != nil {
+			return pgstore.Product{}, ErrProductHasBids
+		}
+		if changes.Description != nil && !strings.HasPrefix(*changes.Description, product.Description) {
+			return pgstore.Product{}, ErrDescriptionRewrite
+		}
+	}
+
+	params := pgstore.UpdateProductParams{
+		ID:          product.ID,
+		ProductName: product.ProductName,
+		Description: product.Description,
+		BasePrice:   product.BasePrice,
+		AuctionEnd:  product.AuctionEnd,
+		CategoryID:  product.CategoryID,
+	}
+	if changes.ProductName != nil {
+		params.ProductName = *changes.ProductName
+	}
+	if changes.Description != nil {
+		params.Description = *changes.Description
+	}
+	if changes.BasePrice != nil {
+		params.BasePrice = *changes.BasePrice
+	}
+	if changes.AuctionEnd != nil {
+		params.AuctionEnd = *changes.AuctionEnd
+	}
+	if changes.CategoryId != nil {
+		params.CategoryID = pgtype.UUID{Bytes: *changes.CategoryId, Valid: true}
+	}
+
+	updated, err := queries.UpdateProduct(ctx, params)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.ConstraintName == "products_category_id_fkey" {
+			return pgstore.Product{}, ErrCategoryNotFound
+		}
+		return pgstore.Product{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Product{}, err
+	}
+	return updated, nil
+}
+
+// WithdrawProduct pulls an open auction. The reason is mandatory once someone
+// has bid on the product since it is shown to the bidders.
+func (ps *ProductService) WithdrawProduct(ctx context.Context, productId uuid.UUID, reason string) (pgstore.Product, error) {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	defer tx.Rollback(ctx)
+	queries := ps.queries.WithTx(tx)
+
+	if _, err := lockOpenProduct(ctx, queries, productId); err != nil {
+		return pgstore.Product{}, err
+	}
+
+	bids, err := queries.CountBidsByProductId(ctx, productId)
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+	if bids > 0 && strings.TrimSpace(reason) == "" {
+		return pgstore.Product{}, ErrWithdrawalReasonMissing
+	}
+
+	withdrawn, err := queries.WithdrawProduct(ctx, pgstore.WithdrawProductParams{
+		ID:               productId,
+		WithdrawalReason: pgtype.Text{String: reason, Valid: reason != ""},
+	})
+	if err != nil {
+		return pgstore.Product{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return pgstore.Product{}, err
+	}
+	return withdrawn, nil
+}
+
+func lockOpenProduct(ctx context.Context, queries *pgstore.Queries, productId uuid.UUID) (pgstore.Product, error) {
+	product, err := queries.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return pgstore.Product{}, ErrProductNotFond
+		}
+		return pgstore.Product{}, err
+	}
+	if product.WithdrawnAt.Valid {
+		return pgstore.Product{}, ErrProductWithdrawn
+	}
+	if product.IsSold || !product.AuctionEnd.After(time.Now()) {
+		return pgstore.Product{}, ErrAuctionEnded
+	}
+	return product, nil
+}
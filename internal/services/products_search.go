@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const sortRelevance ProductSort = "relevance"
+
+type SearchResult struct {
+	CatalogProduct
+	Rank float64 `json:"rank"`
+	// NameHighlight and DescriptionSnippet are html, the seller's text is
+	// escaped and the matches are wrapped in <mark> tags.
+	NameHighlight      string `json:"name_highlight"`
+	DescriptionSnippet string `json:"description_snippet"`
+}
+
+type SearchPage struct {
+	Results    []SearchResult `json:"results"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// buildPrefixTsQuery turns free text into a to_tsquery expression where every
+// word must match and the last one is matched as a prefix, so partial input
+// from a search box already returns results.
+func buildPrefixTsQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] += ":*"
+	return strings.Join(words, " & ")
+}
+
+func (ps *ProductService) SearchProducts(ctx context.Context, text string, filter ProductFilter, cursor string, limit int32) (SearchPage, error) {
+	query := buildPrefixTsQuery(text)
+	if query == "" {
+		return SearchPage{Results: []SearchResult{}}, nil
+	}
+
+	after, err := decodeCatalogCursor(cursor, sortRelevance)
+	if err != nil {
+		return SearchPage{}, err
+	}
+	var cursorId pgtype.UUID
+	var cursorRank pgtype.Float8
+	if after != nil {
+		cursorId = pgtype.UUID{Bytes: after.Id, Valid: true}
+		cursorRank = pgtype.Float8{Float64: after.Rank, Valid: true}
+	}
+
+	rows, err := ps.queries.SearchProducts(ctx, pgstore.SearchProductsParams{
+		Query:        query,
+		Status:       pgtype.Text{String: string(filter.Status), Valid: filter.Status != ""},
+		ViewerID:     optionalUUID(filter.ViewerId),
+		SellerID:     optionalUUID(filter.SellerId),
+		MinPrice:     optionalFloat(filter.MinPrice),
+		MaxPrice:     optionalFloat(filter.MaxPrice),
+		EndingAfter:  optionalTime(filter.EndingAfter),
+		EndingBefore: optionalTime(filter.EndingBefore),
+		CategoryID:   optionalUUID(filter.CategoryId),
+		CursorID:     cursorId,
+		CursorRank:   cursorRank,
+		PageSize:     limit + 1,
+	})
+	if err != nil {
+		return SearchPage{}, err
+	}
+
+	page := SearchPage{Results: make([]SearchResult, 0, len(rows))}
+	for i, r := range rows {
+		if int32(i) == limit {
+			last := rows[i-1]
+			page.NextCursor = encodeCursor(catalogCursor{Sort: sortRelevance, Id: last.ID, Rank: last.Rank})
+			break
+		}
+		page.Results = append(page.Results, SearchResult{
+			CatalogProduct: CatalogProduct{
+				ID:           r.ID,
+				SellerID:     r.SellerID,
+				ProductName:  r.ProductName,
+				Description:  r.Description,
+				BasePrice:    r.BasePrice,
+				CurrentPrice: max(r.BasePrice, r.HighestBid),
+				HighestBid:   r.HighestBid,
+				BidCount:     r.BidCount,
+				CategoryID:   nullableUUID(r.CategoryID),
+				Tags:         r.Tags,
+				AuctionEnd:   r.AuctionEnd,
+				IsSold:       r.IsSold,
+				CreatedAt:    r.CreatedAt,
+			},
+			Rank:               r.Rank,
+			NameHighlight:      r.NameHighlight,
+			DescriptionSnippet: r.DescriptionSnippet,
+		})
+	}
+
+	products := make([]CatalogProduct, len(page.Results))
+	for i := range page.Results {
+		products[i] = page.Results[i].CatalogProduct
+	}
+	if err := ps.attachImages(ctx, products); err != nil {
+		return SearchPage{}, err
+	}
+	for i := range page.Results {
+		page.Results[i].CatalogProduct = products[i]
+	}
+	return page, nil
+}
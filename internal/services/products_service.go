@@ -3,23 +3,34 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/erikgmatos/gobid/internal/storage"
 	"github.com/erikgmatos/gobid/internal/store/pgstore"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type ProductService struct {
 	pool    *pgxpool.Pool
 	queries *pgstore.Queries
+	storage storage.Storage
+	// appURL is the public address used in the invite links.
+	appURL string
+
+	notifications NotificationService
 }
 
-func NewProductService(pool *pgxpool.Pool) ProductService {
+func NewProductService(pool *pgxpool.Pool, imageStorage storage.Storage, appURL string, notifications NotificationService) ProductService {
 	return ProductService{
-		pool:    pool,
-		queries: pgstore.New(pool),
+		pool:          pool,
+		queries:       pgstore.New(pool),
+		storage:       imageStorage,
+		appURL:        strings.TrimSuffix(appURL, "/"),
+		notifications: notifications,
 	}
 }
 
@@ -29,20 +40,57 @@ func (ps *ProductService) CreateProduct(ctx context.Context,
 	description string,
 	basePrice float64,
 	auctionEnd time.Time,
+	categoryId *uuid.UUID,
+	tags []string,
+	visibility Visibility,
 ) (uuid.UUID, error) {
-	id, err := ps.queries.CreateProduct(ctx, pgstore.CreateProductParams{
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	defer tx.Rollback(ctx)
+	queries := ps.queries.WithTx(tx)
+
+	if err := requirePermission(ctx, queries, sellerId, PermSell); err != nil {
+		return uuid.UUID{}, err
+	}
+	id, err := queries.CreateProduct(ctx, pgstore.CreateProductParams{
 		SellerID:    sellerId,
 		ProductName: productName,
 		Description: description,
 		BasePrice:   basePrice,
 		AuctionEnd:  auctionEnd,
+		CategoryID:  optionalUUID(categoryId),
+		Visibility:  string(visibility),
 	})
 	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.ConstraintName == "products_category_id_fkey" {
+			return uuid.UUID{}, ErrCategoryNotFound
+		}
+		return uuid.UUID{}, err
+	}
+
+	for _, tag := range tags {
+		tagId, err := queries.UpsertTag(ctx, normalizeTag(tag))
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+		if err := queries.AddProductTag(ctx, pgstore.AddProductTagParams{ProductID: id, TagID: tagId}); err != nil {
+			return uuid.UUID{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
 		return uuid.UUID{}, err
 	}
 	return id, nil
 }
 
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
 var ErrProductNotFond = errors.New("product not found")
 
 func (ps *ProductService) GetProductById(ctx context.Context, productId uuid.UUID) (pgstore.Product, error) {
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrAuctionNotEnded = errors.New("the auction has not ended yet")
+	ErrNoWinningBid    = errors.New("the auction ended without bids")
+	ErrAlreadySettled  = errors.New("the sale was already settled")
+)
+
+// SettleAuction is called by the seller once the winner paid and received
+// the item. The product is marked as sold, which opens the sale to feedback.
+func (ps *ProductService) SettleAuction(ctx context.Context, productId uuid.UUID) error {
+	tx, err := ps.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := ps.queries.WithTx(tx)
+
+	product, err := queries.GetProductByIdForUpdate(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductNotFond
+		}
+		return err
+	}
+	switch {
+	case product.WithdrawnAt.Valid:
+		return ErrProductWithdrawn
+	case product.IsSold:
+		return ErrAlreadySettled
+	case product.AuctionEnd.After(time.Now()):
+		return ErrAuctionNotEnded
+	}
+	winningBid, err := queries.GetHighestBidByProductId(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNoWinningBid
+		}
+		return err
+	}
+
+	if err := queries.MarkProductSold(ctx, productId); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	err = ps.notifications.Notify(ctx, winningBid.BidderID, NotificationSaleSettled, &productId,
+		fmt.Sprintf("%s is settled", product.ProductName),
+		"The seller confirmed the sale, you can now leave feedback.",
+	)
+	if err != nil {
+		slog.Error("Failed to notify buyer of settlement", "user_id", winningBid.BidderID, "product_id", productId, "error", err)
+	}
+	return nil
+}
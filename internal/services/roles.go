@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleSeller    Role = "seller"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+var Roles = []Role{RoleUser, RoleSeller, RoleModerator, RoleAdmin}
+
+type Permission string
+
+const (
+	PermBid            Permission = "bid"
+	PermSell           Permission = "sell"
+	PermSuspendUsers   Permission = "users:suspend"
+	PermCancelAuctions Permission = "auctions:cancel"
+	PermVoidBids       Permission = "bids:void"
+	PermManageCatalog  Permission = "catalog:manage"
+	PermManageRoles    Permission = "roles:manage"
+	PermViewAuditLog   Permission = "audit:view"
+	PermReviewShills   Permission = "shills:review"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleUser:   {PermBid},
+	RoleSeller: {PermBid, PermSell},
+	RoleModerator: {
+		PermBid, PermSell,
+		PermSuspendUsers, PermCancelAuctions, PermVoidBids, PermViewAuditLog, PermReviewShills,
+	},
+	RoleAdmin: {
+		PermBid, PermSell,
+		PermSuspendUsers, PermCancelAuctions, PermVoidBids, PermViewAuditLog, PermReviewShills,
+		PermManageCatalog, PermManageRoles,
+	},
+}
+
+func (r Role) Can(p Permission) bool {
+	return slices.Contains(rolePermissions[r], p)
+}
+
+// Outranks reports whether r is above other, Roles lists them from the
+// lowest to the highest.
+func (r Role) Outranks(other Role) bool {
+	return slices.Index(Roles, r) > slices.Index(Roles, other)
+}
+
+const (
+	ErrCodeAccountSuspended = "account_suspended"
+	ErrCodePermissionDenied = "permission_denied"
+)
+
+var (
+	ErrAccountSuspended = errors.New("this account is suspended")
+	ErrPermissionDenied = errors.New("you are not allowed to do this")
+)
+
+// Authz is what the permission checks need to know about a user.
+type Authz struct {
+	Role      Role
+	Suspended bool
+}
+
+func (a Authz) Can(p Permission) bool {
+	return !a.Suspended && a.Role.Can(p)
+}
+
+func (us *UserService) GetAuthz(ctx context.Context, userId uuid.UUID) (Authz, error) {
+	return getAuthz(ctx, us.queries, userId)
+}
+
+func getAuthz(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID) (Authz, error) {
+	row, err := queries.GetUserAuthz(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Authz{}, ErrUserNotFound
+		}
+		return Authz{}, err
+	}
+	return Authz{Role: Role(row.Role), Suspended: row.SuspendedAt.Valid}, nil
+}
+
+// requirePermission is the check used by services that are not behind the
+// http permission middleware, like bids placed through the auction rooms.
+func requirePermission(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID, p Permission) error {
+	authz, err := getAuthz(ctx, queries, userId)
+	if err != nil {
+		return err
+	}
+	if authz.Suspended {
+		return ErrAccountSuspended
+	}
+	if !authz.Role.Can(p) {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
+// BecomeSeller lets a plain user open their seller account, the only role
+// change users can make on their own. Users that can already sell are left
+// untouched.
+func (us *UserService) BecomeSeller(ctx context.Context, userId uuid.UUID) error {
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := us.queries.WithTx(tx)
+
+	if err := requireVerifiedEmail(ctx, queries, userId); err != nil {
+		return err
+	}
+	promoted, err := queries.PromoteUserToSeller(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if promoted == 0 {
+		return nil
+	}
+	if err := queries.CreateAdminAuditEntry(ctx, pgstore.CreateAdminAuditEntryParams{
+		ActorID:  optionalUUID(&userId),
+		Action:   AuditRoleChanged,
+		TargetID: userId,
+		Details:  fmt.Sprintf("%s -> %s", RoleUser, RoleSeller),
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRoleCan(t *testing.T) {
+	tests := []struct {
+		role Role
+		perm Permission
+		want bool
+	}{
+		{RoleUser, PermBid, true},
+		{RoleUser, PermSell, false},
+		{RoleSeller, PermSell, true},
+		{RoleSeller, PermSuspendUsers, false},
+		{RoleModerator, PermVoidBids, true},
+		{RoleModerator, PermManageRoles, false},
+		{RoleModerator, PermManageCatalog, false},
+		{RoleAdmin, PermManageRoles, true},
+		{RoleAdmin, PermReviewShills, true},
+		{Role("guest"), PermBid, false},
+	}
+	for _, tt := range tests {
+		if got := tt.role.Can(tt.perm); got != tt.want {
+			t.Errorf("%s.Can(%s) = %v, want %v", tt.role, tt.perm, got, tt.want)
+		}
+	}
+}
+
+func TestRoleOutranks(t *testing.T) {
+	tests := []struct {
+		role, other Role
+		want        bool
+	}{
+		{RoleAdmin, RoleModerator, true},
+		{RoleModerator, RoleSeller, true},
+		{RoleSeller, RoleUser, true},
+		{RoleModerator, RoleModerator, false},
+		{RoleModerator, RoleAdmin, false},
+		{RoleUser, RoleSeller, false},
+	}
+	for _, tt := range tests {
+		if got := tt.role.Outranks(tt.other); got != tt.want {
+			t.Errorf("%s.Outranks(%s) = %v, want %v", tt.role, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestAuthzCan(t *testing.T) {
+	tests := []struct {
+		name  string
+		authz Authz
+		perm  Permission
+		want  bool
+	}{
+		{"active seller sells", Authz{Role: RoleSeller}, PermSell, true},
+		{"suspended seller", Authz{Role: RoleSeller, Suspended: true}, PermSell, false},
+		{"suspended admin", Authz{Role: RoleAdmin, Suspended: true}, PermBid, false},
+		{"user cannot sell", Authz{Role: RoleUser}, PermSell, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.authz.Can(tt.perm); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	suspended := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	tests := []struct {
+		name string
+		row  fakeRow
+		perm Permission
+		want error
+	}{
+		{"allowed", fakeRow{values: []any{"seller", pgtype.Timestamptz{}}}, PermSell, nil},
+		{"missing permission", fakeRow{values: []any{"user", pgtype.Timestamptz{}}}, PermSell, ErrPermissionDenied},
+		{"suspended", fakeRow{values: []any{"admin", suspended}}, PermBid, ErrAccountSuspended},
+		{"unknown user", fakeRow{err: pgx.ErrNoRows}, PermBid, ErrUserNotFound},
+		{"database error", fakeRow{err: errFakeDB}, PermBid, errFakeDB},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{rows: map[string]fakeRow{"GetUserAuthz": tt.row}}
+			err := requirePermission(context.Background(), pgstore.New(db), uuid.New(), tt.perm)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/mailer"
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SearchFrequency string
+
+const (
+	// SearchInstant sends an alert as soon as a matching product is listed.
+	SearchInstant SearchFrequency = "instant"
+	// SearchDaily collects the matches in a digest sent once a day.
+	SearchDaily SearchFrequency = "daily"
+	// SearchOff keeps the search without matching new listings.
+	SearchOff SearchFrequency = "off"
+)
+
+var SearchFrequencies = []SearchFrequency{SearchInstant, SearchDaily, SearchOff}
+
+const (
+	MaxSavedSearches = 20
+	// DefaultSearchDigestInterval is how often the daily digests are sent.
+	DefaultSearchDigestInterval = 24 * time.Hour
+
+	searchMatchTimeout = 30 * time.Second
+	// The time of the last digest is kept in the database, RunDigests checks
+	// that often whether the next one is due.
+	searchDigestJob       = "saved_search_digest"
+	searchDigestPollDelay = time.Minute
+)
+
+var (
+	ErrSavedSearchNotFound  = errors.New("saved search not found")
+	ErrTooManySavedSearches = fmt.Errorf("at most %d searches can be saved", MaxSavedSearches)
+)
+
+type SavedSearchService struct {
+	pool          *pgxpool.Pool
+	queries       *pgstore.Queries
+	mailer        mailer.Mailer
+	appURL        string
+	notifications NotificationService
+}
+
+func NewSavedSearchService(pool *pgxpool.Pool, mail mailer.Mailer, appURL string, notifications NotificationService) SavedSearchService {
+	return SavedSearchService{
+		pool:          pool,
+		queries:       pgstore.New(pool),
+		mailer:        mail,
+		appURL:        strings.TrimSuffix(appURL, "/"),
+		notifications: notifications,
+	}
+}
+
+// SearchCriteria is what a new listing must match, zero fields match
+// everything.
+type SearchCriteria struct {
+	Query      string     `json:"query"`
+	CategoryId *uuid.UUID `json:"category_id"`
+	SellerId   *uuid.UUID `json:"seller_id"`
+	MinPrice   *float64   `json:"min_price"`
+	MaxPrice   *float64   `json:"max_price"`
+}
+
+type SavedSearch struct {
+	ID        uuid.UUID       `json:"id"`
+	Name      string          `json:"name"`
+	Criteria  SearchCriteria  `json:"criteria"`
+	Frequency SearchFrequency `json:"frequency"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func savedSearch(row pgstore.SavedSearch) SavedSearch {
+	return SavedSearch{
+		ID:   row.ID,
+		Name: row.Name,
+		Criteria: SearchCriteria{
+			Query:      row.Query,
+			CategoryId: nullableUUID(row.CategoryID),
+			SellerId:   nullableUUID(row.SellerID),
+			MinPrice:   nullableFloat(row.MinPrice),
+			MaxPrice:   nullableFloat(row.MaxPrice),
+		},
+		Frequency: SearchFrequency(row.Frequency),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+func (ss *SavedSearchService) CreateSavedSearch(ctx context.Context, userId uuid.UUID, name string, criteria SearchCriteria, frequency SearchFrequency) (SavedSearch, error) {
+	count, err := ss.queries.CountSavedSearches(ctx, userId)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+	if count >= MaxSavedSearches {
+		return SavedSearch{}, ErrTooManySavedSearches
+	}
+
+	row, err := ss.queries.CreateSavedSearch(ctx, pgstore.CreateSavedSearchParams{
+		UserID:     userId,
+		Name:       name,
+		Query:      criteria.Query,
+		TsQuery:    buildPrefixTsQuery(criteria.Query),
+		CategoryID: optionalUUID(criteria.CategoryId),
+		SellerID:   optionalUUID(criteria.SellerId),
+		MinPrice:   optionalFloat(criteria.MinPrice),
+		MaxPrice:   optionalFloat(criteria.MaxPrice),
+		Frequency:  string(frequency),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			switch pgErr.ConstraintName {
+			case "saved_searches_category_id_fkey":
+				return SavedSearch{}, ErrCategoryNotFound
+			case "saved_searches_seller_id_fkey":
+				return SavedSearch{}, ErrUserNotFound
+			}
+		}
+		return SavedSearch{}, err
+	}
+	return savedSearch(row), nil
+}
+
+func (ss *SavedSearchService) ListSavedSearches(ctx context.Context, userId uuid.UUID) ([]SavedSearch, error) {
+	rows, err := ss.queries.ListSavedSearches(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	searches := make([]SavedSearch, 0, len(rows))
+	for _, row := range rows {
+		searches = append(searches, savedSearch(row))
+	}
+	return searches, nil
+}
+
+// UpdateSavedSearch renames a search and changes how often it alerts, the
+// criteria are fixed, a different search is saved as a new one.
+func (ss *SavedSearchService) UpdateSavedSearch(ctx context.Context, userId, searchId uuid.UUID, name string, frequency SearchFrequency) (SavedSearch, error) {
+	row, err := ss.queries.UpdateSavedSearch(ctx, pgstore.UpdateSavedSearchParams{
+		ID:        searchId,
+		UserID:    userId,
+		Name:      name,
+		Frequency: string(frequency),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return SavedSearch{}, ErrSavedSearchNotFound
+		}
+		return SavedSearch{}, err
+	}
+	return savedSearch(row), nil
+}
+
+func (ss *SavedSearchService) DeleteSavedSearch(ctx context.Context, userId, searchId uuid.UUID) error {
+	deleted, err := ss.queries.DeleteSavedSearch(ctx, pgstore.DeleteSavedSearchParams{ID: searchId, UserID: userId})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// MatchProduct records which saved searches a listing matches and alerts the
+// owners of the instant ones. It runs after the product was created, edited
+// or made public, so it never slows listing down. Searches already alerted
+// about the product are not alerted again.
+func (ss *SavedSearchService) MatchProduct(productId uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), searchMatchTimeout)
+	defer cancel()
+
+	if err := ss.queries.MatchSavedSearches(ctx, productId); err != nil {
+		slog.Error("Failed to match saved searches", "product_id", productId, "error", err)
+		return
+	}
+	matches, err := ss.queries.ClaimInstantSearchMatches(ctx, productId)
+	if err != nil {
+		slog.Error("Failed to claim saved search matches", "product_id", productId, "error", err)
+		return
+	}
+	for _, m := range matches {
+		err := ss.mailer.Send(ctx, mailer.Message{
+			To:      m.Email,
+			Subject: fmt.Sprintf("New listing for your search %q", m.SearchName),
+			Body: fmt.Sprintf(
+				"Hi %s,\n\n%s was just listed starting at %.2f and ends at %s.\n\n%s/auctions/%s",
+				m.UserName, m.ProductName, m.BasePrice, m.AuctionEnd.Format(time.RFC1123), ss.appURL, m.ProductID,
+			),
+		})
+		if err != nil {
+			slog.Error("Failed to send saved search alert", "user_id", m.UserID, "product_id", m.ProductID, "error", err)
+			// The next digest picks the match up again.
+			ss.releaseMatch(ctx, m.SavedSearchID, m.ProductID)
+			continue
+		}
+		err = ss.notifications.Notify(ctx, m.UserID, NotificationSearchMatch, &m.ProductID,
+			fmt.Sprintf("New listing for your search %q", m.SearchName),
+			fmt.Sprintf("%s was just listed starting at %.2f.", m.ProductName, m.BasePrice),
+		)
+		if err != nil {
+			slog.Error("Failed to notify saved search match", "user_id", m.UserID, "product_id", m.ProductID, "error", err)
+		}
+	}
+}
+
+// releaseMatch gives back a claimed match whose alert could not be sent.
+func (ss *SavedSearchService) releaseMatch(ctx context.Context, searchId, productId uuid.UUID) {
+	err := ss.queries.ReleaseSearchMatch(ctx, pgstore.ReleaseSearchMatchParams{SavedSearchID: searchId, ProductID: productId})
+	if err != nil {
+		slog.Error("Failed to release saved search match", "saved_search_id", searchId, "product_id", productId, "error", err)
+	}
+}
+
+// SendDigests sends every user one notification and one email with the
+// matches not alerted yet. The matches of a digest that could not be sent
+// wait for the next one.
+func (ss *SavedSearchService) SendDigests(ctx context.Context) error {
+	matches, err := ss.queries.ClaimDigestSearchMatches(ctx)
+	if err != nil {
+		return err
+	}
+
+	byUser := make(map[uuid.UUID][]pgstore.ClaimDigestSearchMatchesRow)
+	var order []uuid.UUID
+	for _, m := range matches {
+		if _, ok := byUser[m.UserID]; !ok {
+			order = append(order, m.UserID)
+		}
+		byUser[m.UserID] = append(byUser[m.UserID], m)
+	}
+
+	for _, userId := range order {
+		userMatches := byUser[userId]
+		var body strings.Builder
+		fmt.Fprintf(&body, "Hi %s,\n\nThese listings match your saved searches:\n", userMatches[0].UserName)
+		for _, m := range userMatches {
+			fmt.Fprintf(&body, "\n- %s (%s), starting at %.2f: %s/auctions/%s", m.ProductName, m.SearchName, m.BasePrice, ss.appURL, m.ProductID)
+		}
+		subject := fmt.Sprintf("%d new listings match your saved searches", len(userMatches))
+		err := ss.mailer.Send(ctx, mailer.Message{
+			To:      userMatches[0].Email,
+			Subject: subject,
+			Body:    body.String(),
+		})
+		if err != nil {
+			slog.Error("Failed to send saved search digest", "user_id", userId, "error", err)
+			for _, m := range userMatches {
+				ss.releaseMatch(ctx, m.SavedSearchID, m.ProductID)
+			}
+			continue
+		}
+		err = ss.notifications.Notify(ctx, userId, NotificationSearchMatch, nil, subject,
+			fmt.Sprintf("Open your saved searches to see %s and the other new listings.", userMatches[0].ProductName),
+		)
+		if err != nil {
+			slog.Error("Failed to notify saved search digest", "user_id", userId, "error", err)
+		}
+	}
+	return nil
+}
+
+// RunDigests calls SendDigests once every interval until ctx is done. The
+// time of the last digest is read from the database, restarting the api
+// neither skips nor repeats a digest.
+func (ss *SavedSearchService) RunDigests(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(searchDigestPollDelay)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			due, err := ss.queries.ClaimScheduledJob(ctx, pgstore.ClaimScheduledJobParams{
+				Name:      searchDigestJob,
+				DueBefore: time.Now().Add(-interval),
+			})
+			if err != nil {
+				slog.Error("Failed to check the saved search digest schedule", "error", err)
+				continue
+			}
+			if due == 0 {
+				continue
+			}
+			if err := ss.SendDigests(ctx); err != nil {
+				slog.Error("Failed to send saved search digests", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const ErrCodeBlockedBySeller = "blocked_by_seller"
+
+var (
+	ErrCannotBlockSelf = errors.New("you cannot block yourself")
+	ErrBlockNotFound   = errors.New("user is not blocked")
+	ErrBlockedBySeller = errors.New("the seller does not accept bids from you on their auctions")
+)
+
+// BlockedUser is an entry of a seller's block list.
+type BlockedUser struct {
+	UserID    uuid.UUID `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BlockUser keeps userId from bidding on any auction of sellerId, blocking
+// an already blocked user only updates the reason.
+func (us *UserService) BlockUser(ctx context.Context, sellerId, userId uuid.UUID, reason string) (BlockedUser, error) {
+	if sellerId == userId {
+		return BlockedUser{}, ErrCannotBlockSelf
+	}
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return BlockedUser{}, ErrUserNotFound
+		}
+		return BlockedUser{}, err
+	}
+
+	block, err := us.queries.BlockUser(ctx, pgstore.BlockUserParams{
+		SellerID:      sellerId,
+		BlockedUserID: userId,
+		Reason:        reason,
+	})
+	if err != nil {
+		return BlockedUser{}, err
+	}
+	return BlockedUser{
+		UserID:    block.BlockedUserID,
+		UserName:  user.UserName,
+		Reason:    block.Reason,
+		CreatedAt: block.CreatedAt,
+	}, nil
+}
+
+func (us *UserService) UnblockUser(ctx context.Context, sellerId, userId uuid.UUID) error {
+	deleted, err := us.queries.UnblockUser(ctx, pgstore.UnblockUserParams{SellerID: sellerId, BlockedUserID: userId})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrBlockNotFound
+	}
+	return nil
+}
+
+func (us *UserService) ListBlockedUsers(ctx context.Context, sellerId uuid.UUID) ([]BlockedUser, error) {
+	rows, err := us.queries.ListBlockedUsers(ctx, sellerId)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make([]BlockedUser, 0, len(rows))
+	for _, row := range rows {
+		blocked = append(blocked, BlockedUser{
+			UserID:    row.BlockedUserID,
+			UserName:  row.UserName,
+			Reason:    row.Reason,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return blocked, nil
+}
+
+func (us *UserService) IsBlockedBySeller(ctx context.Context, sellerId, userId uuid.UUID) (bool, error) {
+	return us.queries.IsUserBlockedBySeller(ctx, pgstore.IsUserBlockedBySellerParams{SellerID: sellerId, BlockedUserID: userId})
+}
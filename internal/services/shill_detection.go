@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Reasons a bidder gets flagged as a possible shill of the seller.
+const (
+	ShillReasonSharedIP     = "shared_ip"
+	ShillReasonSingleSeller = "single_seller"
+)
+
+const (
+	ShillFlagOpen      = "open"
+	ShillFlagDismissed = "dismissed"
+	ShillFlagConfirmed = "confirmed"
+)
+
+const (
+	ErrCodeSelfBid        = "self_bid"
+	ErrCodeRelatedAccount = "related_account"
+)
+
+// shillSingleSellerMinAuctions is how many auctions of the same seller a
+// bidder has to bid on, never winning, before being flagged.
+const shillSingleSellerMinAuctions = 3
+
+const shillInspectionTimeout = 10 * time.Second
+
+var (
+	ErrSelfBid                = errors.New("you cannot bid on your own auctions")
+	ErrRelatedAccountBid      = errors.New("this account was found to be related to the seller and cannot bid on their auctions")
+	ErrShillFlagNotFound      = errors.New("shill flag not found or already reviewed")
+	ErrInvalidShillFlagStatus = errors.New("status must be open, dismissed or confirmed")
+)
+
+// inspectBid looks for signs that bidderId bids on behalf of the seller and
+// queues flags for the moderators. It runs after the bid was accepted, so it
+// never slows bidding down.
+func (bs *BidsService) inspectBid(productId, sellerId, bidderId uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), shillInspectionTimeout)
+	defer cancel()
+
+	flagged, err := bs.queries.FlagSharedIP(ctx, pgstore.FlagSharedIPParams{
+		SellerID:  sellerId,
+		BidderID:  bidderId,
+		ProductID: productId,
+	})
+	if err != nil {
+		slog.Error("Failed to inspect bid", "reason", ShillReasonSharedIP, "product_id", productId, "error", err)
+	} else if flagged > 0 {
+		slog.Warn("Possible shill bidder flagged", "reason", ShillReasonSharedIP, "seller_id", sellerId, "bidder_id", bidderId)
+	}
+
+	flagged, err = bs.queries.FlagSingleSellerBidder(ctx, pgstore.FlagSingleSellerBidderParams{
+		BidderID:    bidderId,
+		SellerID:    sellerId,
+		ProductID:   productId,
+		MinAuctions: shillSingleSellerMinAuctions,
+	})
+	if err != nil {
+		slog.Error("Failed to inspect bid", "reason", ShillReasonSingleSeller, "product_id", productId, "error", err)
+	} else if flagged > 0 {
+		slog.Warn("Possible shill bidder flagged", "reason", ShillReasonSingleSeller, "seller_id", sellerId, "bidder_id", bidderId)
+	}
+}
+
+func (ms *ModerationService) ListShillFlags(ctx context.Context, status string, limit int32) ([]pgstore.ShillFlag, error) {
+	if status != ShillFlagOpen && status != ShillFlagDismissed && status != ShillFlagConfirmed {
+		return nil, ErrInvalidShillFlagStatus
+	}
+	flags, err := ms.queries.ListShillFlags(ctx, pgstore.ListShillFlagsParams{Status: status, PageSize: limit})
+	if err != nil {
+		return nil, err
+	}
+	if flags == nil {
+		flags = []pgstore.ShillFlag{}
+	}
+	return flags, nil
+}
+
+// ReviewShillFlag closes an open flag. A confirmed flag keeps the bidder away
+// from every auction of that seller.
+func (ms *ModerationService) ReviewShillFlag(ctx context.Context, actorId, flagId uuid.UUID, status string) (pgstore.ShillFlag, error) {
+	var flag pgstore.ShillFlag
+	err := ms.inTx(ctx, func(queries *pgstore.Queries) (pgstore.CreateAdminAuditEntryParams, error) {
+		var err error
+		flag, err = queries.ReviewShillFlag(ctx, pgstore.ReviewShillFlagParams{
+			Status:     status,
+			ReviewedBy: optionalUUID(&actorId),
+			ID:         flagId,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return pgstore.CreateAdminAuditEntryParams{}, ErrShillFlagNotFound
+			}
+			return pgstore.CreateAdminAuditEntryParams{}, err
+		}
+		return pgstore.CreateAdminAuditEntryParams{
+			ActorID:  optionalUUID(&actorId),
+			Action:   AuditShillFlagReviewed,
+			TargetID: flag.ID,
+			Details:  flag.Reason + ": " + status,
+		}, nil
+	})
+	return flag, err
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TOTP as described in RFC 6238 with the parameters every authenticator app
+// supports: SHA1, 6 digits and 30 second steps.
+const (
+	totpIssuer      = "GoBid"
+	totpDigits      = 6
+	totpPeriod      = 30
+	totpSecretBytes = 20
+	// totpSkew is how many steps before and after the current one are
+	// accepted to cope with clock drift.
+	totpSkew = 1
+
+	// Invalid codes are counted per user in the login failures table, so
+	// logging in again does not give a fresh set of attempts.
+	totpFailureScope    = "totp"
+	maxTOTPFailures     = 10
+	totpFailureWindow   = 15 * time.Minute
+	totpLockoutDuration = 15 * time.Minute
+)
+
+var (
+	ErrTwoFactorNotEnabled  = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrTwoFactorLocked is an ErrInvalidTwoFactorCode, callers that do not
+	// tell them apart treat it as a wrong code.
+	ErrTwoFactorLocked = fmt.Errorf("%w, too many attempts, try again later", ErrInvalidTwoFactorCode)
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func newTOTPSecret() (string, error) {
+	raw := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(raw), nil
+}
+
+func totpURI(secret, account string) string {
+	label := url.PathEscape(totpIssuer + ":" + account)
+	query := url.Values{
+		"secret":    {secret},
+		"issuer":    {totpIssuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(totpDigits)},
+		"period":    {fmt.Sprint(totpPeriod)},
+	}
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1_000_000)
+}
+
+// matchTOTP returns the step code belongs to when it is valid at now.
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+	code = strings.ReplaceAll(code, " ", "")
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// verifyTOTP checks code against the confirmed secret of the user. A code
+// is accepted once, replaying it fails.
+func verifyTOTP(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID, code string) error {
+	totp, err := queries.GetUserTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if !totp.ConfirmedAt.Valid {
+		return ErrTwoFactorNotEnabled
+	}
+	return useTOTPCode(ctx, queries, totp, code)
+}
+
+// useTOTPCode checks code against the secret of totp and uses up its step.
+// Invalid codes count towards the lockout of the user.
+func useTOTPCode(ctx context.Context, queries *pgstore.Queries, totp pgstore.UserTotp, code string) error {
+	if err := checkTOTPLockout(ctx, queries, totp.UserID); err != nil {
+		return err
+	}
+	step, ok := matchTOTP(totp.Secret, code, time.Now())
+	if !ok {
+		return recordTOTPFailure(ctx, queries, totp.UserID)
+	}
+	used, err := queries.UseTOTPStep(ctx, pgstore.UseTOTPStepParams{Step: step, UserID: totp.UserID})
+	if err != nil {
+		return err
+	}
+	if used == 0 {
+		return recordTOTPFailure(ctx, queries, totp.UserID)
+	}
+	return queries.ClearLoginFailures(ctx, pgstore.ClearLoginFailuresParams{Scope: totpFailureScope, Subject: totp.UserID.String()})
+}
+
+func checkTOTPLockout(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID) error {
+	failure, err := queries.GetLoginFailure(ctx, pgstore.GetLoginFailureParams{Scope: totpFailureScope, Subject: userId.String()})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if failure.LockedUntil.Valid && failure.LockedUntil.Time.After(time.Now()) {
+		return ErrTwoFactorLocked
+	}
+	return nil
+}
+
+// recordTOTPFailure counts an invalid code and returns the error to report,
+// ErrTwoFactorLocked when it was the last attempt allowed.
+func recordTOTPFailure(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID) error {
+	failure, err := queries.RecordLoginFailure(ctx, pgstore.RecordLoginFailureParams{
+		Scope:       totpFailureScope,
+		Subject:     userId.String(),
+		WindowStart: time.Now().Add(-totpFailureWindow),
+	})
+	if err != nil {
+		return err
+	}
+	if failure.FailedCount < maxTOTPFailures {
+		return ErrInvalidTwoFactorCode
+	}
+	err = queries.LockLoginSubject(ctx, pgstore.LockLoginSubjectParams{
+		Scope:       totpFailureScope,
+		Subject:     userId.String(),
+		LockedUntil: pgtype.Timestamptz{Time: time.Now().Add(totpLockoutDuration), Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	return ErrTwoFactorLocked
+}
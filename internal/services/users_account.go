@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountDeletionBlockedError is returned while the user still takes part
+// in auctions that need them to be reachable. Won and sold auctions block
+// the deletion until the seller settles them, however long ago they ended.
+type AccountDeletionBlockedError struct {
+	OpenAuctions   int64 `json:"open_auctions"`
+	LeadingBids    int64 `json:"leading_bids"`
+	UnsettledWins  int64 `json:"unsettled_wins"`
+	UnsettledSales int64 `json:"unsettled_sales"`
+}
+
+func (e *AccountDeletionBlockedError) Error() string {
+	return fmt.Sprintf(
+		"account cannot be deleted: %d open auctions, %d auctions you are winning, %d unsettled wins and %d unsettled sales",
+		e.OpenAuctions, e.LeadingBids, e.UnsettledWins, e.UnsettledSales,
+	)
+}
+
+// AccountExport is everything gobid keeps about a user.
+type AccountExport struct {
+	ExportedAt time.Time         `json:"exported_at"`
+	Profile    Profile           `json:"profile"`
+	Products   []ExportedProduct `json:"products"`
+	Bids       []ExportedBid     `json:"bids"`
+	Sessions   []Session         `json:"sessions"`
+	// Feedback is what the user left on others, the feedback they received
+	// is on their public profile.
+	SavedSearches []SavedSearch      `json:"saved_searches"`
+	Watchlist     []ExportedWatch    `json:"watchlist"`
+	Notifications []Notification     `json:"notifications"`
+	Feedback      []ExportedFeedback `json:"feedback"`
+}
+
+type ExportedProduct struct {
+	ID               uuid.UUID  `json:"id"`
+	ProductName      string     `json:"product_name"`
+	Description      string     `json:"description"`
+	BasePrice        float64    `json:"base_price"`
+	AuctionEnd       time.Time  `json:"auction_end"`
+	IsSold           bool       `json:"is_sold"`
+	CategoryID       *uuid.UUID `json:"category_id"`
+	WithdrawnAt      *time.Time `json:"withdrawn_at"`
+	WithdrawalReason *string    `json:"withdrawal_reason"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type ExportedBid struct {
+	ID          uuid.UUID  `json:"id"`
+	ProductID   uuid.UUID  `json:"product_id"`
+	ProductName string     `json:"product_name"`
+	BidAmount   float64    `json:"bid_amount"`
+	CreatedAt   time.Time  `json:"created_at"`
+	VoidedAt    *time.Time `json:"voided_at"`
+}
+
+type ExportedWatch struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	WatchedAt   time.Time `json:"watched_at"`
+}
+
+// ExportedFeedback is feedback the user left on others.
+type ExportedFeedback struct {
+	ID            uuid.UUID    `json:"id"`
+	ProductID     uuid.UUID    `json:"product_id"`
+	ProductName   string       `json:"product_name"`
+	RecipientID   uuid.UUID    `json:"recipient_id"`
+	RecipientRole FeedbackRole `json:"recipient_role"`
+	Score         int32        `json:"score"`
+	Comment       string       `json:"comment"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+func (us *UserService) ExportAccount(ctx context.Context, userId uuid.UUID) (AccountExport, error) {
+	profile, err := us.GetProfile(ctx, userId)
+	if err != nil {
+		return AccountExport{}, err
+	}
+
+	products, err := us.queries.ListProductsBySeller(ctx, userId)
+	if err != nil {
+		return AccountExport{}, err
+	}
+	exportedProducts := make([]ExportedProduct, 0, len(products))
+	for _, p := range products {
+		exportedProducts = append(exportedProducts, ExportedProduct{
+			ID:               p.ID,
+			ProductName:      p.ProductName,
+			Description:      p.Description,
+			BasePrice:        p.BasePrice,
+			AuctionEnd:       p.AuctionEnd,
+			IsSold:           p.IsSold,
+			CategoryID:       nullableUUID(p.CategoryID),
+			WithdrawnAt:      nullableTime(p.WithdrawnAt),
+			WithdrawalReason: nullableText(p.WithdrawalReason),
+			CreatedAt:        p.CreatedAt,
+			UpdatedAt:        p.UpdatedAt,
+		})
+	}
+
+	bids, err := us.queries.ListBidsByBidder(ctx, userId)
+	if err != nil {
+		return AccountExport{}, err
+	}
+	exportedBids := make([]ExportedBid, 0, len(bids))
+	for _, b := range bids {
+		exportedBids = append(exportedBids, ExportedBid{
+			ID:          b.ID,
+			ProductID:   b.ProductID,
+			ProductName: b.ProductName,
+			BidAmount:   b.BidAmount,
+			CreatedAt:   b.CreatedAt,
+			VoidedAt:    nullableTime(b.VoidedAt),
+		})
+	}
+
+	sessions, err := us.ListSessions(ctx, userId, uuid.Nil)
+	if err != nil {
+		return AccountExport{}, err
+	}
+
+	searches, err := us.queries.ListSavedSearches(ctx, userId)
+	if err != nil {
+		return AccountExport{}, err
+	}
+	exportedSearches := make([]SavedSearch, 0, len(searches))
+	for _, s := range searches {
+		exportedSearches = append(exportedSearches, savedSearch(s))
+	}
+
+	watches, err := us.queries.ListWatchlistByUser(ctx, userId)
+	if err != nil {
+		return AccountExport{}, err
+	}
+	exportedWatches := make([]ExportedWatch, 0, len(watches))
+	for _, w := range watches {
+		exportedWatches = append(exportedWatches, ExportedWatch{
+			ProductID:   w.ProductID,
+			ProductName: w.ProductName,
+			WatchedAt:   w.CreatedAt,
+		})
+	}
+
+	notifications, err := us.queries.ListNotificationsByUser(ctx, userId)
+	if err != nil {
+		return AccountExport{}, err
+	}
+	exportedNotifications := make([]Notification, 0, len(notifications))
+	for _, n := range notifications {
+		exportedNotifications = append(exportedNotifications, notification(n))
+	}
+
+	feedback, err := us.queries.ListFeedbackByAuthor(ctx, userId)
+	if err != nil {
+		return AccountExport{}, err
+	}
+	exportedFeedback := make([]ExportedFeedback, 0, len(feedback))
+	for _, f := range feedback {
+		exportedFeedback = append(exportedFeedback, ExportedFeedback{
+			ID:            f.ID,
+			ProductID:     f.ProductID,
+			ProductName:   f.ProductName,
+			RecipientID:   f.RecipientID,
+			RecipientRole: FeedbackRole(f.RecipientRole),
+			Score:         f.Score,
+			Comment:       f.Comment,
+			CreatedAt:     f.CreatedAt,
+		})
+	}
+
+	return AccountExport{
+		ExportedAt:    time.Now(),
+		Profile:       profile,
+		Products:      exportedProducts,
+		Bids:          exportedBids,
+		Sessions:      sessions,
+		SavedSearches: exportedSearches,
+		Watchlist:     exportedWatches,
+		Notifications: exportedNotifications,
+		Feedback:      exportedFeedback,
+	}, nil
+}
+
+// DeleteAccount anonymizes the user after checking their password, and their
+// second factor when 2FA is on. The row stays so bids and products keep
+// their references, everything that identifies the person is dropped and
+// their saved searches, watchlist and notifications are deleted.
+func (us *UserService) DeleteAccount(ctx context.Context, userId uuid.UUID, password, code, recoveryCode string) error {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrInvalidCredentials
+		}
+		return err
+	}
+	twoFactor, err := us.twoFactorEnabled(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if twoFactor {
+		if code == "" && recoveryCode == "" {
+			return ErrInvalidTwoFactorCode
+		}
+		if err := us.VerifySecondFactor(ctx, userId, code, recoveryCode); err != nil {
+			return err
+		}
+	}
+
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := us.queries.WithTx(tx)
+
+	blockers, err := queries.GetAccountDeletionBlockers(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if blockers.OpenAuctions > 0 || blockers.LeadingBids > 0 || blockers.UnsettledWins > 0 || blockers.UnsettledSales > 0 {
+		return &AccountDeletionBlockedError{
+			OpenAuctions:   blockers.OpenAuctions,
+			LeadingBids:    blockers.LeadingBids,
+			UnsettledWins:  blockers.UnsettledWins,
+			UnsettledSales: blockers.UnsettledSales,
+		}
+	}
+
+	if _, err := queries.AnonymizeUser(ctx, userId); err != nil {
+		return err
+	}
+	subject := loginAccountSubject(user.Email)
+	cleanups := []func() error{
+		func() error { return queries.DeleteUserTOTP(ctx, userId) },
+		func() error { return queries.DeleteRecoveryCodes(ctx, userId) },
+		func() error { return queries.InvalidatePasswordResetTokens(ctx, userId) },
+		func() error { return queries.DeletePersonalAccessTokens(ctx, userId) },
+		func() error { return queries.DeleteUserSavedSearches(ctx, userId) },
+		func() error { return queries.DeleteUserWatchlist(ctx, userId) },
+		func() error { return queries.DeleteUserNotifications(ctx, userId) },
+		func() error {
+			_, err := queries.DeleteUserSessions(ctx, pgstore.DeleteUserSessionsParams{UserID: userId, KeepID: uuid.Nil})
+			return err
+		},
+		func() error {
+			return queries.DeleteAuthAuditEntries(ctx, pgstore.DeleteAuthAuditEntriesParams{
+				UserID: optionalUUID(&userId),
+				Email:  subject,
+			})
+		},
+		func() error {
+			return queries.ClearLoginFailures(ctx, pgstore.ClearLoginFailuresParams{Scope: loginScopeAccount, Subject: subject})
+		},
+	}
+	for _, cleanup := range cleanups {
+		if err := cleanup(); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/mailer"
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PasswordResetTokenTTL = time.Hour
+	passwordHashCost      = 12
+
+	// Reset requests are counted per email and per IP address inside
+	// passwordResetWindow, in the table used for the failed logins.
+	passwordResetWindow = time.Hour
+	maxResetsPerEmail   = 3
+	maxResetsPerIP      = 20
+	resetScopeEmail     = "reset_email"
+	resetScopeIP        = "reset_ip"
+
+	passwordResetMailTimeout = 30 * time.Second
+)
+
+var (
+	ErrInvalidResetToken    = errors.New("reset token is invalid or has expired")
+	ErrTooManyResetRequests = errors.New("too many password reset requests, try again later")
+)
+
+// ChangePassword replaces the password of a logged in user, the current one
+// must be given again.
+func (us *UserService) ChangePassword(ctx context.Context, userId uuid.UUID, currentPassword, newPassword string) error {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(currentPassword)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrInvalidCredentials
+		}
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), passwordHashCost)
+	if err != nil {
+		return err
+	}
+	return us.queries.UpdateUserPassword(ctx, pgstore.UpdateUserPasswordParams{ID: userId, PasswordHash: hash})
+}
+
+// RequestPasswordReset mails a reset link to the owner of email. Only the
+// request limits are checked before returning, the account is looked up and
+// the mail sent in the background, so neither the outcome nor the response
+// time reveals which emails have an account. Unknown addresses still count
+// towards the limits.
+func (us *UserService) RequestPasswordReset(ctx context.Context, email, ip string) error {
+	if err := us.countResetRequest(ctx, resetScopeIP, ip, maxResetsPerIP); err != nil {
+		return err
+	}
+	if err := us.countResetRequest(ctx, resetScopeEmail, loginAccountSubject(email), maxResetsPerEmail); err != nil {
+		return err
+	}
+	go us.sendPasswordReset(email)
+	return nil
+}
+
+func (us *UserService) sendPasswordReset(email string) {
+	ctx, cancel := context.WithTimeout(context.Background(), passwordResetMailTimeout)
+	defer cancel()
+
+	user, err := us.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			slog.Error("Failed to look up password reset account", "error", err)
+		}
+		return
+	}
+
+	token, hash, err := newResetToken()
+	if err != nil {
+		slog.Error("Failed to create password reset token", "user_id", user.ID, "error", err)
+		return
+	}
+	if err := us.storeResetToken(ctx, user.ID, hash); err != nil {
+		slog.Error("Failed to store password reset token", "user_id", user.ID, "error", err)
+		return
+	}
+
+	err = us.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your GoBid password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to choose a new password. It expires in %s and can only be used once.\n\n%s/reset-password?token=%s\n\nIf you did not ask for it you can ignore this email.",
+			user.UserName, PasswordResetTokenTTL, us.appURL, token,
+		),
+	})
+	if err != nil {
+		slog.Error("Failed to send password reset", "user_id", user.ID, "error", err)
+	}
+}
+
+func (us *UserService) storeResetToken(ctx context.Context, userId uuid.UUID, hash []byte) error {
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := us.queries.WithTx(tx)
+
+	// Only the latest link works.
+	if err := queries.InvalidatePasswordResetTokens(ctx, userId); err != nil {
+		return err
+	}
+	if err := queries.CreatePasswordResetToken(ctx, pgstore.CreatePasswordResetTokenParams{
+		UserID:    userId,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(PasswordResetTokenTTL),
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// ResetPassword sets a new password using a token sent by
+// RequestPasswordReset. It returns the user so the caller can revoke the
+// sessions of that account.
+func (us *UserService) ResetPassword(ctx context.Context, token, newPassword string) (uuid.UUID, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), passwordHashCost)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	defer tx.Rollback(ctx)
+	queries := us.queries.WithTx(tx)
+
+	userId, err := queries.ConsumePasswordResetToken(ctx, hashResetToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.UUID{}, ErrInvalidResetToken
+		}
+		return uuid.UUID{}, err
+	}
+	if err := queries.UpdateUserPassword(ctx, pgstore.UpdateUserPasswordParams{ID: userId, PasswordHash: hash}); err != nil {
+		return uuid.UUID{}, err
+	}
+	if err := queries.InvalidatePasswordResetTokens(ctx, userId); err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.UUID{}, err
+	}
+	return userId, nil
+}
+
+// countResetRequest records a reset request for subject and returns
+// ErrTooManyResetRequests once more than max were made inside the window.
+func (us *UserService) countResetRequest(ctx context.Context, scope, subject string, max int) error {
+	requests, err := us.queries.RecordLoginFailure(ctx, pgstore.RecordLoginFailureParams{
+		Scope:       scope,
+		Subject:     subject,
+		WindowStart: time.Now().Add(-passwordResetWindow),
+	})
+	if err != nil {
+		return err
+	}
+	if int(requests.FailedCount) > max {
+		return ErrTooManyResetRequests
+	}
+	return nil
+}
+
+// newResetToken returns the token sent to the user and the hash stored in
+// the database, a leaked table cannot be used to reset passwords.
+func newResetToken() (string, []byte, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashResetToken(token), nil
+}
+
+func hashResetToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrDuplicatedUsername = errors.New("username already exists")
+)
+
+const usersUserNameUniqueKey = "users_user_name_key"
+
+// Profile is what the logged in user sees about themselves. The sqlc user
+// rows carry the password hash, so they are never encoded directly.
+type Profile struct {
+	ID       uuid.UUID `json:"id"`
+	UserName string    `json:"user_name"`
+	Email    string    `json:"email"`
+	Bio      string    `json:"bio"`
+	// EmailVerified tells if the user may bid and sell.
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type SellerStats struct {
+	AuctionsListed    int64 `json:"auctions_listed"`
+	AuctionsActive    int64 `json:"auctions_active"`
+	AuctionsCompleted int64 `json:"auctions_completed"`
+}
+
+// PublicProfile is what anyone can see about a user.
+type PublicProfile struct {
+	ID          uuid.UUID   `json:"id"`
+	UserName    string      `json:"user_name"`
+	Bio         string      `json:"bio"`
+	JoinedAt    time.Time   `json:"joined_at"`
+	SellerStats SellerStats `json:"seller_stats"`
+	Reputation  Reputation  `json:"reputation"`
+}
+
+func (us *UserService) GetProfile(ctx context.Context, userId uuid.UUID) (Profile, error) {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Profile{}, ErrUserNotFound
+		}
+		return Profile{}, err
+	}
+	return Profile{
+		ID:            user.ID,
+		UserName:      user.UserName,
+		Email:         user.Email,
+		Bio:           user.Bio,
+		EmailVerified: user.EmailVerifiedAt.Valid,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}, nil
+}
+
+// UpdateProfile changes the username and bio, nil fields are kept.
+func (us *UserService) UpdateProfile(ctx context.Context, userId uuid.UUID, userName, bio *string) (Profile, error) {
+	current, err := us.GetProfile(ctx, userId)
+	if err != nil {
+		return Profile{}, err
+	}
+	params := pgstore.UpdateUserProfileParams{ID: userId, UserName: current.UserName, Bio: current.Bio}
+	if userName != nil {
+		params.UserName = *userName
+	}
+	if bio != nil {
+		params.Bio = *bio
+	}
+
+	user, err := us.queries.UpdateUserProfile(ctx, params)
+	if err != nil {
+		var pgError *pgconn.PgError
+		if errors.As(err, &pgError) && pgError.Code == pgUniqueViolation && pgError.ConstraintName == usersUserNameUniqueKey {
+			return Profile{}, ErrDuplicatedUsername
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Profile{}, ErrUserNotFound
+		}
+		return Profile{}, err
+	}
+	return Profile{
+		ID:            user.ID,
+		UserName:      user.UserName,
+		Email:         user.Email,
+		Bio:           user.Bio,
+		EmailVerified: user.EmailVerifiedAt.Valid,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}, nil
+}
+
+func (us *UserService) GetPublicProfile(ctx context.Context, userId uuid.UUID) (PublicProfile, error) {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PublicProfile{}, ErrUserNotFound
+		}
+		return PublicProfile{}, err
+	}
+	stats, err := us.queries.GetSellerStats(ctx, userId)
+	if err != nil {
+		return PublicProfile{}, err
+	}
+	reputation, err := getReputation(ctx, us.queries, userId)
+	if err != nil {
+		return PublicProfile{}, err
+	}
+	return PublicProfile{
+		ID:       user.ID,
+		UserName: user.UserName,
+		Bio:      user.Bio,
+		JoinedAt: user.CreatedAt,
+		SellerStats: SellerStats{
+			AuctionsListed:    stats.Listed,
+			AuctionsActive:    stats.Active,
+			AuctionsCompleted: stats.Completed,
+		},
+		Reputation: reputation,
+	}, nil
+}
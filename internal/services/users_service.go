@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"strings"
 
+	"github.com/erikgmatos/gobid/internal/mailer"
 	"github.com/erikgmatos/gobid/internal/store/pgstore"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -13,14 +15,31 @@ import (
 )
 
 type UserService struct {
-	pool    *pgxpool.Pool
-	queries *pgstore.Queries
+	pool            *pgxpool.Pool
+	queries         *pgstore.Queries
+	mailer          mailer.Mailer
+	appURL          string
+	verificationKey []byte
+	loginProtection LoginProtection
 }
 
-func NewUserService(pool *pgxpool.Pool) UserService {
+type UserServiceConfig struct {
+	Mailer mailer.Mailer
+	// AppURL is the public address used in the links sent by email.
+	AppURL string
+	// VerificationKey signs the email verification links.
+	VerificationKey []byte
+	LoginProtection LoginProtection
+}
+
+func NewUserService(pool *pgxpool.Pool, config UserServiceConfig) UserService {
 	return UserService{
-		pool:    pool,
-		queries: pgstore.New(pool),
+		pool:            pool,
+		queries:         pgstore.New(pool),
+		mailer:          config.Mailer,
+		appURL:          strings.TrimSuffix(config.AppURL, "/"),
+		verificationKey: config.VerificationKey,
+		loginProtection: config.LoginProtection,
 	}
 }
 
@@ -30,7 +49,7 @@ var (
 )
 
 func (us *UserService) CreateUser(ctx context.Context, userName, email, password, bio string) (uuid.UUID, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
 	if err != nil {
 		return uuid.UUID{}, err
 	}
@@ -53,20 +72,73 @@ func (us *UserService) CreateUser(ctx context.Context, userName, email, password
 	return id, nil
 }
 
-func (us *UserService) AuthenticateUser(ctx context.Context, email, password string) (uuid.UUID, error) {
+// AuthenticateUser checks the credentials sent from ip. Failed attempts are
+// throttled per account and per ip, see LoginProtection.
+func (us *UserService) AuthenticateUser(ctx context.Context, email, password, ip string) (uuid.UUID, error) {
+	subject := loginAccountSubject(email)
+	for _, check := range [][2]string{{loginScopeIP, ip}, {loginScopeAccount, subject}} {
+		if err := us.checkLoginAllowed(ctx, check[0], check[1]); err != nil {
+			var throttled *LoginThrottledError
+			if errors.As(err, &throttled) {
+				us.audit(ctx, nil, subject, ip, AuditLoginThrottled)
+			}
+			return uuid.UUID{}, err
+		}
+	}
+
 	user, err := us.queries.GetUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return uuid.UUID{}, ErrInvalidCredentials
+			return uuid.UUID{}, us.failLogin(ctx, nil, subject, ip)
 		}
 		return uuid.UUID{}, err
 	}
 	err = bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password))
 	if err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			return uuid.UUID{}, ErrInvalidCredentials
+			return uuid.UUID{}, us.failLogin(ctx, &user, subject, ip)
 		}
 		return uuid.UUID{}, err
 	}
+	// Checked after the password so the response does not reveal that an
+	// account exists and is suspended.
+	if user.SuspendedAt.Valid {
+		return uuid.UUID{}, ErrAccountSuspended
+	}
+
+	if err := us.queries.ClearLoginFailures(ctx, pgstore.ClearLoginFailuresParams{Scope: loginScopeAccount, Subject: subject}); err != nil {
+		return uuid.UUID{}, err
+	}
+	us.audit(ctx, &user.ID, subject, ip, AuditLoginSucceeded)
 	return user.ID, nil
 }
+
+// failLogin records a failed attempt and returns ErrInvalidCredentials, the
+// caller is not told when the attempt locked the account.
+func (us *UserService) failLogin(ctx context.Context, user *pgstore.GetUserByEmailRow, subject, ip string) error {
+	var userId *uuid.UUID
+	if user != nil {
+		userId = &user.ID
+	}
+	us.audit(ctx, userId, subject, ip, AuditLoginFailed)
+
+	accountLocked, err := us.recordLoginFailure(ctx, loginScopeAccount, subject, us.loginProtection.MaxAccountFailures)
+	if err != nil {
+		return err
+	}
+	if accountLocked {
+		us.audit(ctx, userId, subject, ip, AuditAccountLocked)
+		if user != nil {
+			us.notifyLockout(ctx, *user, ip)
+		}
+	}
+
+	ipLocked, err := us.recordLoginFailure(ctx, loginScopeIP, ip, us.loginProtection.MaxIPFailures)
+	if err != nil {
+		return err
+	}
+	if ipLocked {
+		us.audit(ctx, userId, subject, ip, AuditIPLocked)
+	}
+	return ErrInvalidCredentials
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+)
+
+// maxUserAgentLength keeps a hostile client from storing an arbitrary
+// amount of data with every session.
+const maxUserAgentLength = 512
+
+// DefaultSessionPruneInterval is how often sessions that expired are deleted.
+const DefaultSessionPruneInterval = time.Hour
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session describes where a user is logged in, the session token itself
+// never leaves the sessions table.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+// SessionToken is the cookie session a tracked session belongs to, revoking
+// the tracked session destroys it.
+type SessionToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (us *UserService) StartSession(ctx context.Context, userId uuid.UUID, token SessionToken, ip, userAgent string) (uuid.UUID, error) {
+	return us.queries.CreateUserSession(ctx, pgstore.CreateUserSessionParams{
+		UserID:    userId,
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+		IpAddress: ip,
+		UserAgent: truncateUserAgent(userAgent),
+	})
+}
+
+// TouchSession records that the session was used and reports whether it is
+// still active. last_seen_at is only written once a minute, or right away
+// when the cookie session got a new token.
+func (us *UserService) TouchSession(ctx context.Context, userId, sessionId uuid.UUID, token SessionToken, ip, userAgent string) (bool, error) {
+	return us.queries.TouchUserSession(ctx, pgstore.TouchUserSessionParams{
+		Token:     token.Token,
+		ExpiresAt: token.ExpiresAt,
+		IpAddress: ip,
+		UserAgent: truncateUserAgent(userAgent),
+		ID:        sessionId,
+		UserID:    userId,
+	})
+}
+
+func (us *UserService) ListSessions(ctx context.Context, userId, currentId uuid.UUID) ([]Session, error) {
+	rows, err := us.queries.ListUserSessions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, Session{
+			ID:         row.ID,
+			IPAddress:  row.IpAddress,
+			UserAgent:  row.UserAgent,
+			CreatedAt:  row.CreatedAt,
+			LastSeenAt: row.LastSeenAt,
+			Current:    row.ID == currentId,
+		})
+	}
+	return sessions, nil
+}
+
+func (us *UserService) RevokeSession(ctx context.Context, userId, sessionId uuid.UUID) error {
+	deleted, err := us.queries.DeleteUserSession(ctx, pgstore.DeleteUserSessionParams{ID: sessionId, UserID: userId})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeSessions ends every session of the user except keepId, pass
+// uuid.Nil to end all of them. The cookie sessions are destroyed with them.
+// It returns the ids of the revoked sessions.
+func (us *UserService) RevokeSessions(ctx context.Context, userId, keepId uuid.UUID) ([]uuid.UUID, error) {
+	return us.queries.DeleteUserSessions(ctx, pgstore.DeleteUserSessionsParams{UserID: userId, KeepID: keepId})
+}
+
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) <= maxUserAgentLength {
+		return userAgent
+	}
+	return strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+}
+
+// PruneSessions deletes the sessions whose cookie session expired.
+func (us *UserService) PruneSessions(ctx context.Context) (int64, error) {
+	return us.queries.DeleteExpiredUserSessions(ctx)
+}
+
+// RunSessionPruning calls PruneSessions every interval until ctx is done.
+func (us *UserService) RunSessionPruning(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := us.PruneSessions(ctx); err != nil {
+				slog.Error("Failed to prune expired sessions", "error", err)
+			}
+		}
+	}
+}
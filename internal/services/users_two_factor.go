@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	recoveryCodesCount = 10
+
+	ErrCodeTwoFactorRequired = "two_factor_required"
+	ErrCodeInvalidTwoFactor  = "invalid_two_factor_code"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotStarted     = errors.New("start the two-factor enrolment first")
+	ErrBidConfirmationRequired = errors.New("bids above your confirmation threshold need a two-factor code")
+)
+
+type TwoFactorEnrolment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled                  bool     `json:"enabled"`
+	BidConfirmationThreshold *float64 `json:"bid_confirmation_threshold"`
+}
+
+// StartTwoFactor creates a new secret for the user. It only becomes active
+// once ConfirmTwoFactor receives a code generated from it.
+func (us *UserService) StartTwoFactor(ctx context.Context, userId uuid.UUID) (TwoFactorEnrolment, error) {
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TwoFactorEnrolment{}, ErrUserNotFound
+		}
+		return TwoFactorEnrolment{}, err
+	}
+	enabled, err := us.twoFactorEnabled(ctx, userId)
+	if err != nil {
+		return TwoFactorEnrolment{}, err
+	}
+	if enabled {
+		return TwoFactorEnrolment{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return TwoFactorEnrolment{}, err
+	}
+	if err := us.queries.UpsertUserTOTP(ctx, pgstore.UpsertUserTOTPParams{UserID: userId, Secret: secret}); err != nil {
+		return TwoFactorEnrolment{}, err
+	}
+	return TwoFactorEnrolment{Secret: secret, URI: totpURI(secret, user.Email)}, nil
+}
+
+// ConfirmTwoFactor enables 2FA and returns the recovery codes, they are not
+// stored in plain text and cannot be shown again.
+func (us *UserService) ConfirmTwoFactor(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	totp, err := us.queries.GetUserTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTwoFactorNotStarted
+		}
+		return nil, err
+	}
+	if totp.ConfirmedAt.Valid {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	// The step of the code is used up, it cannot be replayed right after.
+	if err := useTOTPCode(ctx, us.queries, totp, code); err != nil {
+		return nil, err
+	}
+
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+	queries := us.queries.WithTx(tx)
+
+	if err := queries.ConfirmUserTOTP(ctx, userId); err != nil {
+		return nil, err
+	}
+	codes, err := replaceRecoveryCodes(ctx, queries, userId)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes invalidates the previous codes.
+func (us *UserService) RegenerateRecoveryCodes(ctx context.Context, userId uuid.UUID, code string) ([]string, error) {
+	if err := verifyTOTP(ctx, us.queries, userId, code); err != nil {
+		return nil, err
+	}
+
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	codes, err := replaceRecoveryCodes(ctx, us.queries.WithTx(tx), userId)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (us *UserService) DisableTwoFactor(ctx context.Context, userId uuid.UUID, code string) error {
+	if err := verifyTOTP(ctx, us.queries, userId, code); err != nil {
+		return err
+	}
+
+	tx, err := us.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	queries := us.queries.WithTx(tx)
+
+	if err := queries.DeleteUserTOTP(ctx, userId); err != nil {
+		return err
+	}
+	if err := queries.DeleteRecoveryCodes(ctx, userId); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (us *UserService) GetTwoFactorStatus(ctx context.Context, userId uuid.UUID) (TwoFactorStatus, error) {
+	totp, err := us.queries.GetUserTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return TwoFactorStatus{}, nil
+		}
+		return TwoFactorStatus{}, err
+	}
+	status := TwoFactorStatus{Enabled: totp.ConfirmedAt.Valid}
+	if totp.BidConfirmationThreshold.Valid {
+		status.BidConfirmationThreshold = &totp.BidConfirmationThreshold.Float64
+	}
+	return status, nil
+}
+
+// SetBidConfirmationThreshold makes bids above threshold require a 2FA code,
+// nil turns the check off.
+func (us *UserService) SetBidConfirmationThreshold(ctx context.Context, userId uuid.UUID, threshold *float64) error {
+	updated, err := us.queries.SetBidConfirmationThreshold(ctx, pgstore.SetBidConfirmationThresholdParams{
+		UserID:                   userId,
+		BidConfirmationThreshold: optionalFloat(threshold),
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrTwoFactorNotEnabled
+	}
+	return nil
+}
+
+// TwoFactorEnabled tells the login whether a second step is needed.
+func (us *UserService) TwoFactorEnabled(ctx context.Context, userId uuid.UUID) (bool, error) {
+	return us.twoFactorEnabled(ctx, userId)
+}
+
+func (us *UserService) twoFactorEnabled(ctx context.Context, userId uuid.UUID) (bool, error) {
+	status, err := us.GetTwoFactorStatus(ctx, userId)
+	return status.Enabled, err
+}
+
+// VerifySecondFactor accepts either a TOTP code or one of the recovery codes,
+// recovery codes work only once. Both share the attempt count of the user.
+func (us *UserService) VerifySecondFactor(ctx context.Context, userId uuid.UUID, code, recoveryCode string) error {
+	if recoveryCode == "" {
+		return verifyTOTP(ctx, us.queries, userId, code)
+	}
+	if err := checkTOTPLockout(ctx, us.queries, userId); err != nil {
+		return err
+	}
+	used, err := us.queries.UseRecoveryCode(ctx, pgstore.UseRecoveryCodeParams{
+		UserID:   userId,
+		CodeHash: hashRecoveryCode(recoveryCode),
+	})
+	if err != nil {
+		return err
+	}
+	if used == 0 {
+		return recordTOTPFailure(ctx, us.queries, userId)
+	}
+	return nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID) ([]string, error) {
+	if err := queries.DeleteRecoveryCodes(ctx, userId); err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := strings.ToLower(totpEncoding.EncodeToString(raw))
+		code = code[:8] + "-" + code[8:]
+		if err := queries.CreateRecoveryCode(ctx, pgstore.CreateRecoveryCodeParams{
+			UserID:   userId,
+			CodeHash: hashRecoveryCode(code),
+		}); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), " ", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return sum[:]
+}
+
+// requireBidConfirmation checks the 2FA code of bids above the threshold the
+// bidder configured.
+func requireBidConfirmation(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID, amount float64, code string) error {
+	totp, err := queries.GetUserTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	threshold := totp.BidConfirmationThreshold
+	if !totp.ConfirmedAt.Valid || !threshold.Valid || amount <= threshold.Float64 {
+		return nil
+	}
+	if code == "" {
+		return ErrBidConfirmationRequired
+	}
+	return verifyTOTP(ctx, queries, userId, code)
+}
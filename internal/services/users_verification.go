@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/mailer"
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	EmailVerificationTTL       = 48 * time.Hour
+	VerificationResendInterval = 5 * time.Minute
+
+	ErrCodeEmailNotVerified = "email_not_verified"
+)
+
+var (
+	ErrEmailNotVerified         = errors.New("verify your email address before bidding or selling")
+	ErrEmailAlreadyVerified     = errors.New("email address is already verified")
+	ErrVerificationThrottled    = errors.New("a verification email was sent recently, try again in a few minutes")
+	ErrInvalidVerificationToken = errors.New("verification link is invalid or has expired")
+)
+
+// SendVerificationEmail mails a signed verification link to the user. Only
+// one email is sent per VerificationResendInterval.
+func (us *UserService) SendVerificationEmail(ctx context.Context, userId uuid.UUID) error {
+	user, err := us.queries.ClaimVerificationEmail(ctx, pgstore.ClaimVerificationEmailParams{
+		ID:         userId,
+		SentBefore: time.Now().Add(-VerificationResendInterval),
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		verified, err := us.queries.IsUserEmailVerified(ctx, userId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if verified {
+			return ErrEmailAlreadyVerified
+		}
+		return ErrVerificationThrottled
+	}
+
+	token := us.signVerificationToken(userId, user.Email, time.Now().Add(EmailVerificationTTL))
+	return us.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your GoBid email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nConfirm your email address to start bidding and selling on GoBid. The link expires in %s.\n\n%s/api/v1/users/verify-email?token=%s\n",
+			user.UserName, EmailVerificationTTL, us.appURL, token,
+		),
+	})
+}
+
+// VerifyEmail checks a link sent by SendVerificationEmail. Links stop working
+// when the user changes their email address since it is part of the
+// signature.
+func (us *UserService) VerifyEmail(ctx context.Context, token string) error {
+	encodedPayload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return ErrInvalidVerificationToken
+	}
+	rawPayload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	rawId, rawExpiry, ok := strings.Cut(string(rawPayload), "|")
+	if !ok {
+		return ErrInvalidVerificationToken
+	}
+	userId, err := uuid.Parse(rawId)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	expiry, err := strconv.ParseInt(rawExpiry, 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return ErrInvalidVerificationToken
+	}
+
+	user, err := us.queries.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+	expected := us.signVerificationToken(userId, user.Email, time.Unix(expiry, 0))
+	if !hmac.Equal([]byte(expected), []byte(encodedPayload+"."+signature)) {
+		return ErrInvalidVerificationToken
+	}
+
+	updated, err := us.queries.MarkUserEmailVerified(ctx, pgstore.MarkUserEmailVerifiedParams{ID: userId, Email: user.Email})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return ErrInvalidVerificationToken
+	}
+	return nil
+}
+
+// RequireVerifiedEmail is the policy check run before bidding or selling.
+func (us *UserService) RequireVerifiedEmail(ctx context.Context, userId uuid.UUID) error {
+	return requireVerifiedEmail(ctx, us.queries, userId)
+}
+
+func requireVerifiedEmail(ctx context.Context, queries *pgstore.Queries, userId uuid.UUID) error {
+	verified, err := queries.IsUserEmailVerified(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if !verified {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+func (us *UserService) signVerificationToken(userId uuid.UUID, email string, expiry time.Time) string {
+	payload := fmt.Sprintf("%s|%d", userId, expiry.Unix())
+	mac := hmac.New(sha256.New, us.verificationKey)
+	mac.Write([]byte(payload + "|" + email))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." +
+		base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/mailer"
+	"github.com/erikgmatos/gobid/internal/store/pgstore"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrNotWatching = errors.New("product is not in your watchlist")
+
+// EndingSoonAlerts tells watchers that an auction is about to end. Every
+// Interval the auctions ending within Window are looked up, each watcher is
+// told once.
+type EndingSoonAlerts struct {
+	Interval time.Duration
+	Window   time.Duration
+}
+
+var DefaultEndingSoonAlerts = EndingSoonAlerts{
+	Interval: time.Minute,
+	Window:   time.Hour,
+}
+
+type WatchlistService struct {
+	pool          *pgxpool.Pool
+	queries       *pgstore.Queries
+	mailer        mailer.Mailer
+	appURL        string
+	notifications NotificationService
+}
+
+func NewWatchlistService(pool *pgxpool.Pool, mail mailer.Mailer, appURL string, notifications NotificationService) WatchlistService {
+	return WatchlistService{
+		pool:          pool,
+		queries:       pgstore.New(pool),
+		mailer:        mail,
+		appURL:        strings.TrimSuffix(appURL, "/"),
+		notifications: notifications,
+	}
+}
+
+// WatchedAuction is a watchlist entry with the state of the auction at the
+// time of the request.
+type WatchedAuction struct {
+	ProductID    uuid.UUID     `json:"product_id"`
+	SellerID     uuid.UUID     `json:"seller_id"`
+	ProductName  string        `json:"product_name"`
+	CurrentPrice float64       `json:"current_price"`
+	BidCount     int64         `json:"bid_count"`
+	Status       ProductStatus `json:"status"`
+	AuctionEnd   time.Time     `json:"auction_end"`
+	// TimeLeftSeconds is 0 once the auction has ended.
+	TimeLeftSeconds int64     `json:"time_left_seconds"`
+	WatchedAt       time.Time `json:"watched_at"`
+}
+
+// StatusWithdrawn only shows up in watchlists, the catalog leaves withdrawn
+// auctions out.
+const StatusWithdrawn ProductStatus = "withdrawn"
+
+func (ws *WatchlistService) Watch(ctx context.Context, userId, productId uuid.UUID) error {
+	product, err := ws.queries.GetProductById(ctx, productId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrProductNotFond
+		}
+		return err
+	}
+	visible, err := canView(ctx, ws.queries, product, &userId)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !visible:
+		return ErrProductNotFond
+	case product.WithdrawnAt.Valid:
+		return ErrProductWithdrawn
+	case product.IsSold || !product.AuctionEnd.After(time.Now()):
+		return ErrAuctionEnded
+	}
+	return ws.queries.WatchProduct(ctx, pgstore.WatchProductParams{UserID: userId, ProductID: productId})
+}
+
+func (ws *WatchlistService) Unwatch(ctx context.Context, userId, productId uuid.UUID) error {
+	removed, err := ws.queries.UnwatchProduct(ctx, pgstore.UnwatchProductParams{UserID: userId, ProductID: productId})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrNotWatching
+	}
+	return nil
+}
+
+// ListWatchlist returns the open auctions first, ending soonest first, then
+// the ones that are over.
+func (ws *WatchlistService) ListWatchlist(ctx context.Context, userId uuid.UUID) ([]WatchedAuction, error) {
+	rows, err := ws.queries.ListWatchlist(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	watched := make([]WatchedAuction, 0, len(rows))
+	for _, r := range rows {
+		status := StatusActive
+		switch {
+		case r.WithdrawnAt.Valid:
+			status = StatusWithdrawn
+		case r.IsSold:
+			status = StatusSold
+		case !r.AuctionEnd.After(now):
+			status = StatusEnded
+		}
+		var timeLeft int64
+		if status == StatusActive {
+			timeLeft = int64(r.AuctionEnd.Sub(now).Seconds())
+		}
+		watched = append(watched, WatchedAuction{
+			ProductID:       r.ID,
+			SellerID:        r.SellerID,
+			ProductName:     r.ProductName,
+			CurrentPrice:    max(r.BasePrice, r.HighestBid),
+			BidCount:        r.BidCount,
+			Status:          status,
+			AuctionEnd:      r.AuctionEnd,
+			TimeLeftSeconds: timeLeft,
+			WatchedAt:       r.WatchedAt,
+		})
+	}
+	return watched, nil
+}
+
+// CountWatchers is shown to the seller only, it is a hint of the interest in
+// the auction.
+func (ws *WatchlistService) CountWatchers(ctx context.Context, productId uuid.UUID) (int64, error) {
+	return ws.queries.CountProductWatchers(ctx, productId)
+}
+
+// NotifyEndingSoon notifies and emails the watchers of the auctions ending
+// before now+window that were not told yet.
+func (ws *WatchlistService) NotifyEndingSoon(ctx context.Context, window time.Duration) error {
+	watches, err := ws.queries.ClaimEndingSoonWatches(ctx, time.Now().Add(window))
+	if err != nil {
+		return err
+	}
+	for _, w := range watches {
+		err := ws.notifications.Notify(ctx, w.UserID, NotificationAuctionEnding, &w.ProductID,
+			fmt.Sprintf("%s is ending soon", w.ProductName),
+			fmt.Sprintf("An auction in your watchlist ends at %s.", w.AuctionEnd.Format(time.RFC1123)),
+		)
+		if err != nil {
+			slog.Error("Failed to notify ending soon", "user_id", w.UserID, "product_id", w.ProductID, "error", err)
+		}
+		err = ws.mailer.Send(ctx, mailer.Message{
+			To:      w.Email,
+			Subject: fmt.Sprintf("%s is ending soon", w.ProductName),
+			Body: fmt.Sprintf(
+				"Hi %s,\n\nAn auction in your watchlist ends at %s, there is still time to bid.\n\n%s/auctions/%s",
+				w.UserName, w.AuctionEnd.Format(time.RFC1123), ws.appURL, w.ProductID,
+			),
+		})
+		if err != nil {
+			slog.Error("Failed to send ending soon email", "user_id", w.UserID, "product_id", w.ProductID, "error", err)
+		}
+	}
+	return nil
+}
+
+// RunEndingSoonAlerts calls NotifyEndingSoon every alerts.Interval until ctx
+// is done.
+func (ws *WatchlistService) RunEndingSoonAlerts(ctx context.Context, alerts EndingSoonAlerts) {
+	ticker := time.NewTicker(alerts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := ws.NotifyEndingSoon(ctx, alerts.Window); err != nil {
+				slog.Error("Failed to send ending soon alerts", "error", err)
+			}
+		}
+	}
+}
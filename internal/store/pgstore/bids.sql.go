@@ -7,15 +7,31 @@ package pgstore
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countBidsByProductId = `-- name: CountBidsByProductId :one
+
+SELECT COUNT(*) FROM bids
+WHERE product_id = $1
+  AND voided_at IS NULL
+`
+
+func (q *Queries) CountBidsByProductId(ctx context.Context, productID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countBidsByProductId, productID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createBid = `-- name: CreateBid :one
 
 INSERT INTO bids ("product_id", "bidder_id", "bid_amount")
 VALUES ($1, $2, $3)
-RETURNING id, product_id, bidder_id, bid_amount, created_at
+RETURNING id, product_id, bidder_id, bid_amount, created_at, voided_at, void_reason
 `
 
 type CreateBidParams struct {
@@ -33,14 +49,38 @@ func (q *Queries) CreateBid(ctx context.Context, arg CreateBidParams) (Bid, erro
 		&i.BidderID,
 		&i.BidAmount,
 		&i.CreatedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
+	)
+	return i, err
+}
+
+const getBidById = `-- name: GetBidById :one
+
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, void_reason FROM bids
+WHERE id = $1
+`
+
+func (q *Queries) GetBidById(ctx context.Context, id uuid.UUID) (Bid, error) {
+	row := q.db.QueryRow(ctx, getBidById, id)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.BidderID,
+		&i.BidAmount,
+		&i.CreatedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
 	)
 	return i, err
 }
 
 const getBidsByProductId = `-- name: GetBidsByProductId :many
 
-SELECT id, product_id, bidder_id, bid_amount, created_at FROM bids
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, void_reason FROM bids
 WHERE product_id = $1
+  AND voided_at IS NULL
 ORDER BY bid_amount DESC
 `
 
@@ -59,6 +99,8 @@ func (q *Queries) GetBidsByProductId(ctx context.Context, productID uuid.UUID) (
 			&i.BidderID,
 			&i.BidAmount,
 			&i.CreatedAt,
+			&i.VoidedAt,
+			&i.VoidReason,
 		); err != nil {
 			return nil, err
 		}
@@ -72,8 +114,9 @@ func (q *Queries) GetBidsByProductId(ctx context.Context, productID uuid.UUID) (
 
 const getHighestBidByProductId = `-- name: GetHighestBidByProductId :one
 
-SELECT id, product_id, bidder_id, bid_amount, created_at FROM bids
+SELECT id, product_id, bidder_id, bid_amount, created_at, voided_at, void_reason FROM bids
 WHERE product_id = $1
+  AND voided_at IS NULL
 ORDER BY bid_amount DESC
 LIMIT 1
 `
@@ -87,6 +130,153 @@ func (q *Queries) GetHighestBidByProductId(ctx context.Context, productID uuid.U
 		&i.BidderID,
 		&i.BidAmount,
 		&i.CreatedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
+	)
+	return i, err
+}
+
+const listBidHistory = `-- name: ListBidHistory :many
+
+WITH bidder_numbers AS (
+  SELECT
+    bidder_id,
+    ROW_NUMBER() OVER (ORDER BY MIN(created_at), bidder_id) AS bidder_number
+  FROM bids
+  WHERE product_id = $1
+    AND voided_at IS NULL
+  GROUP BY bidder_id
+)
+SELECT b.id, b.bid_amount, b.created_at, n.bidder_number
+FROM bids b
+JOIN bidder_numbers n ON n.bidder_id = b.bidder_id
+WHERE b.product_id = $1
+  AND b.voided_at IS NULL
+  AND ($2::timestamptz IS NULL
+    OR (b.created_at, b.id) < ($2, $3::uuid))
+ORDER BY b.created_at DESC, b.id DESC
+LIMIT $4
+`
+
+type ListBidHistoryParams struct {
+	ProductID       uuid.UUID          `json:"product_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	PageSize        int32              `json:"page_size"`
+}
+
+type ListBidHistoryRow struct {
+	ID           uuid.UUID `json:"id"`
+	BidAmount    float64   `json:"bid_amount"`
+	CreatedAt    time.Time `json:"created_at"`
+	BidderNumber int64     `json:"bidder_number"`
+}
+
+func (q *Queries) ListBidHistory(ctx context.Context, arg ListBidHistoryParams) ([]ListBidHistoryRow, error) {
+	rows, err := q.db.Query(ctx, listBidHistory,
+		arg.ProductID,
+		arg.CursorCreatedAt,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBidHistoryRow
+	for rows.Next() {
+		var i ListBidHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.BidAmount,
+			&i.CreatedAt,
+			&i.BidderNumber,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBidsByBidder = `-- name: ListBidsByBidder :many
+SELECT
+  b.id,
+  b.product_id,
+  p.product_name,
+  b.bid_amount,
+  b.created_at,
+  b.voided_at
+FROM bids b
+JOIN products p ON p.id = b.product_id
+WHERE b.bidder_id = $1
+ORDER BY b.created_at DESC
+`
+
+type ListBidsByBidderRow struct {
+	ID          uuid.UUID          `json:"id"`
+	ProductID   uuid.UUID          `json:"product_id"`
+	ProductName string             `json:"product_name"`
+	BidAmount   float64            `json:"bid_amount"`
+	CreatedAt   time.Time          `json:"created_at"`
+	VoidedAt    pgtype.Timestamptz `json:"voided_at"`
+}
+
+func (q *Queries) ListBidsByBidder(ctx context.Context, bidderID uuid.UUID) ([]ListBidsByBidderRow, error) {
+	rows, err := q.db.Query(ctx, listBidsByBidder, bidderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBidsByBidderRow
+	for rows.Next() {
+		var i ListBidsByBidderRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProductID,
+			&i.ProductName,
+			&i.BidAmount,
+			&i.CreatedAt,
+			&i.VoidedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const voidBid = `-- name: VoidBid :one
+
+UPDATE bids
+SET voided_at = now(), void_reason = $2
+WHERE id = $1
+  AND voided_at IS NULL
+RETURNING id, product_id, bidder_id, bid_amount, created_at, voided_at, void_reason
+`
+
+type VoidBidParams struct {
+	ID         uuid.UUID   `json:"id"`
+	VoidReason pgtype.Text `json:"void_reason"`
+}
+
+func (q *Queries) VoidBid(ctx context.Context, arg VoidBidParams) (Bid, error) {
+	row := q.db.QueryRow(ctx, voidBid, arg.ID, arg.VoidReason)
+	var i Bid
+	err := row.Scan(
+		&i.ID,
+		&i.ProductID,
+		&i.BidderID,
+		&i.BidAmount,
+		&i.CreatedAt,
+		&i.VoidedAt,
+		&i.VoidReason,
 	)
 	return i, err
 }
@@ -0,0 +1,10 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS withdrawn_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS withdrawal_reason TEXT;
+---- create above / drop below ----
+ALTER TABLE products
+  DROP COLUMN IF EXISTS withdrawal_reason,
+  DROP COLUMN IF EXISTS withdrawn_at;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
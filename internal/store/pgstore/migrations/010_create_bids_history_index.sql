@@ -0,0 +1,6 @@
+-- Write your migrate up statements here
+CREATE INDEX IF NOT EXISTS bids_product_id_created_at_id_idx ON bids (product_id, created_at DESC, id DESC);
+---- create above / drop below ----
+DROP INDEX IF EXISTS bids_product_id_created_at_id_idx;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
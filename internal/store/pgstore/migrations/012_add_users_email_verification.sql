@@ -0,0 +1,10 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS verification_sent_at TIMESTAMPTZ;
+---- create above / drop below ----
+ALTER TABLE users
+  DROP COLUMN IF EXISTS verification_sent_at,
+  DROP COLUMN IF EXISTS email_verified_at;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,27 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS login_failures (
+  scope TEXT NOT NULL,
+  subject TEXT NOT NULL,
+  failed_count INTEGER NOT NULL DEFAULT 0,
+  last_failed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  locked_until TIMESTAMPTZ,
+
+  PRIMARY KEY (scope, subject)
+);
+
+CREATE TABLE IF NOT EXISTS auth_audit_log (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  email TEXT NOT NULL,
+  ip_address TEXT NOT NULL,
+  event TEXT NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS auth_audit_log_user_id_created_at_idx ON auth_audit_log (user_id, created_at DESC);
+---- create above / drop below ----
+DROP TABLE IF EXISTS auth_audit_log;
+DROP TABLE IF EXISTS login_failures;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
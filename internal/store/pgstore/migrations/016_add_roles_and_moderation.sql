@@ -0,0 +1,36 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user'
+    CHECK (role IN ('user', 'seller', 'moderator', 'admin')),
+  ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS suspension_reason TEXT;
+
+-- Everyone could sell before roles existed.
+UPDATE users SET role = 'seller';
+
+ALTER TABLE bids
+  ADD COLUMN IF NOT EXISTS voided_at TIMESTAMPTZ,
+  ADD COLUMN IF NOT EXISTS void_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+  action TEXT NOT NULL,
+  target_id UUID NOT NULL,
+  details TEXT NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_target_id_idx ON admin_audit_log (target_id, created_at DESC);
+---- create above / drop below ----
+DROP TABLE IF EXISTS admin_audit_log;
+ALTER TABLE bids
+  DROP COLUMN IF EXISTS void_reason,
+  DROP COLUMN IF EXISTS voided_at;
+ALTER TABLE users
+  DROP COLUMN IF EXISTS suspension_reason,
+  DROP COLUMN IF EXISTS suspended_at,
+  DROP COLUMN IF EXISTS role;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS user_sessions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  ip_address TEXT NOT NULL,
+  user_agent TEXT NOT NULL,
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_sessions_user_id_idx ON user_sessions (user_id);
+---- create above / drop below ----
+DROP TABLE IF EXISTS user_sessions;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,10 @@
+-- Write your migrate up statements here
+-- Deleted accounts are anonymized instead of removed, their bids and
+-- products keep pointing at the row.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+---- create above / drop below ----
+ALTER TABLE users
+  DROP COLUMN IF EXISTS deleted_at;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
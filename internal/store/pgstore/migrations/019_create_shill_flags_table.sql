@@ -0,0 +1,25 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS shill_flags (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  seller_id UUID NOT NULL REFERENCES users(id),
+  bidder_id UUID NOT NULL REFERENCES users(id),
+  product_id UUID NOT NULL REFERENCES products(id),
+  reason TEXT NOT NULL,
+  details TEXT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'open'
+    CHECK (status IN ('open', 'dismissed', 'confirmed')),
+  reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  -- A pair of accounts is flagged once per reason, a dismissed flag is not
+  -- raised again on the next bid.
+  UNIQUE (seller_id, bidder_id, reason)
+);
+
+CREATE INDEX IF NOT EXISTS shill_flags_status_created_at_idx ON shill_flags (status, created_at);
+---- create above / drop below ----
+DROP TABLE IF EXISTS shill_flags;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,15 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS seller_blocks (
+  seller_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  blocked_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  reason TEXT NOT NULL DEFAULT '',
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (seller_id, blocked_user_id),
+  CHECK (seller_id <> blocked_user_id)
+);
+---- create above / drop below ----
+DROP TABLE IF EXISTS seller_blocks;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,24 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS bidders_require_verified_email BOOLEAN NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS bidders_min_account_age_days INTEGER,
+  ADD COLUMN IF NOT EXISTS bidders_min_feedback_score INTEGER,
+  ADD COLUMN IF NOT EXISTS bidders_invite_only BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS auction_invitations (
+  product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (product_id, user_id)
+);
+---- create above / drop below ----
+DROP TABLE IF EXISTS auction_invitations;
+ALTER TABLE products
+  DROP COLUMN IF EXISTS bidders_invite_only,
+  DROP COLUMN IF EXISTS bidders_min_feedback_score,
+  DROP COLUMN IF EXISTS bidders_min_account_age_days,
+  DROP COLUMN IF EXISTS bidders_require_verified_email;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
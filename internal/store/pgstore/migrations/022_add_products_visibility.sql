@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+ALTER TABLE products
+  ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'unlisted', 'invite_only'));
+
+CREATE TABLE IF NOT EXISTS auction_invite_links (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  token_hash BYTEA NOT NULL UNIQUE,
+  max_uses INTEGER,
+  use_count INTEGER NOT NULL DEFAULT 0,
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS auction_invite_links_product_id_idx ON auction_invite_links (product_id);
+---- create above / drop below ----
+DROP TABLE IF EXISTS auction_invite_links;
+ALTER TABLE products DROP COLUMN IF EXISTS visibility;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
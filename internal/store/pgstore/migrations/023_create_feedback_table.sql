@@ -0,0 +1,23 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS feedback (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  author_id UUID NOT NULL REFERENCES users(id),
+  recipient_id UUID NOT NULL REFERENCES users(id),
+  recipient_role TEXT NOT NULL
+    CHECK (recipient_role IN ('buyer', 'seller')),
+  score INTEGER NOT NULL
+    CHECK (score IN (-1, 0, 1)),
+  comment TEXT NOT NULL,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  -- The buyer and the seller rate each other once per sale.
+  UNIQUE (product_id, author_id)
+);
+
+CREATE INDEX IF NOT EXISTS feedback_recipient_id_created_at_idx ON feedback (recipient_id, created_at);
+---- create above / drop below ----
+DROP TABLE IF EXISTS feedback;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
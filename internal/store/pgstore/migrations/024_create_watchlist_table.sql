@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS watchlist (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  -- Set once the watcher was told the auction is about to end, so they are
+  -- only told once.
+  ending_soon_notified_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (user_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS watchlist_product_id_idx ON watchlist (product_id);
+---- create above / drop below ----
+DROP TABLE IF EXISTS watchlist;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,39 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS saved_searches (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  query TEXT NOT NULL,
+  -- The to_tsquery expression built from query, empty when the search only
+  -- uses filters.
+  ts_query TEXT NOT NULL,
+  category_id UUID REFERENCES categories(id) ON DELETE CASCADE,
+  seller_id UUID REFERENCES users(id) ON DELETE CASCADE,
+  min_price FLOAT,
+  max_price FLOAT,
+  frequency TEXT NOT NULL DEFAULT 'instant'
+    CHECK (frequency IN ('instant', 'daily', 'off')),
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS saved_searches_user_id_idx ON saved_searches (user_id);
+
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+  saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+  product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+  notified_at TIMESTAMPTZ,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+
+  PRIMARY KEY (saved_search_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS saved_search_matches_pending_idx ON saved_search_matches (created_at)
+  WHERE notified_at IS NULL;
+---- create above / drop below ----
+DROP TABLE IF EXISTS saved_search_matches;
+DROP TABLE IF EXISTS saved_searches;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+-- The token of the cookie session lets a revoked session be destroyed
+-- directly, expires_at lets rows of sessions that ran out be pruned.
+ALTER TABLE user_sessions
+  ADD COLUMN IF NOT EXISTS token TEXT,
+  ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+UPDATE user_sessions
+SET expires_at = created_at + interval '24 hours'
+WHERE expires_at IS NULL;
+
+ALTER TABLE user_sessions
+  ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS user_sessions_expires_at_idx ON user_sessions (expires_at);
+---- create above / drop below ----
+DROP INDEX IF EXISTS user_sessions_expires_at_idx;
+ALTER TABLE user_sessions
+  DROP COLUMN IF EXISTS expires_at,
+  DROP COLUMN IF EXISTS token;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,24 @@
+-- Write your migrate up statements here
+-- Whether a product shows up in the catalog and search results of a viewer,
+-- viewer_id is NULL for anonymous visitors. Unlisted auctions are only
+-- listed for their seller, invite only ones for the invited users too.
+CREATE OR REPLACE FUNCTION product_listed_for(
+  product_id UUID,
+  seller_id UUID,
+  visibility TEXT,
+  viewer_id UUID
+) RETURNS BOOLEAN
+LANGUAGE sql STABLE
+AS $$
+  SELECT visibility = 'public'
+    OR seller_id = viewer_id
+    OR (visibility = 'invite_only' AND EXISTS (
+      SELECT 1 FROM auction_invitations ai
+      WHERE ai.product_id = product_listed_for.product_id
+        AND ai.user_id = viewer_id
+    ))
+$$;
+---- create above / drop below ----
+DROP FUNCTION IF EXISTS product_listed_for(UUID, UUID, TEXT, UUID);
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,11 @@
+-- Write your migrate up statements here
+-- When periodic jobs last ran, so their schedule survives restarts and is
+-- shared by every instance of the api.
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+  name TEXT PRIMARY KEY,
+  last_run_at TIMESTAMPTZ NOT NULL
+);
+---- create above / drop below ----
+DROP TABLE IF EXISTS scheduled_jobs;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
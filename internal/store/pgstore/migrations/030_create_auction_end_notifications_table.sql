@@ -0,0 +1,31 @@
+-- Write your migrate up statements here
+-- Winners and losers are told once per auction, whether the auction room or
+-- the periodic job gets to the ended auction first.
+DELETE FROM notifications n
+USING notifications d
+WHERE n.kind IN ('auction_won', 'auction_lost', 'payment_due')
+  AND d.user_id = n.user_id
+  AND d.kind = n.kind
+  AND d.product_id = n.product_id
+  AND (d.created_at, d.id) < (n.created_at, n.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS notifications_auction_end_once_idx ON notifications (user_id, kind, product_id)
+  WHERE kind IN ('auction_won', 'auction_lost', 'payment_due');
+
+-- The ended auctions whose bidders were notified.
+CREATE TABLE IF NOT EXISTS auction_end_notifications (
+  product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Auctions that ended before this migration are not announced again.
+INSERT INTO auction_end_notifications (product_id)
+SELECT id FROM products
+WHERE auction_end <= now()
+ON CONFLICT (product_id) DO NOTHING;
+---- create above / drop below ----
+DROP TABLE IF EXISTS auction_end_notifications;
+DROP INDEX IF EXISTS notifications_auction_end_once_idx;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
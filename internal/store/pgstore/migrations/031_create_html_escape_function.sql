@@ -0,0 +1,17 @@
+-- Write your migrate up statements here
+-- Escapes the characters that are markup in html, search highlights run on
+-- the escaped text so the only tags they contain are the <mark> ones.
+CREATE OR REPLACE FUNCTION html_escape(value TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE STRICT
+AS $$
+  SELECT replace(replace(replace(replace(replace(value,
+    '&', '&amp;'),
+    '<', '&lt;'),
+    '>', '&gt;'),
+    '"', '&quot;'),
+    '''', '&#39;')
+$$;
+---- create above / drop below ----
+DROP FUNCTION IF EXISTS html_escape(TEXT);
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -8,26 +8,180 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type Bid struct {
-	ID        uuid.UUID `json:"id"`
+type AdminAuditLog struct {
+	ID        uuid.UUID   `json:"id"`
+	ActorID   pgtype.UUID `json:"actor_id"`
+	Action    string      `json:"action"`
+	TargetID  uuid.UUID   `json:"target_id"`
+	Details   string      `json:"details"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type AuctionInvitation struct {
 	ProductID uuid.UUID `json:"product_id"`
-	BidderID  uuid.UUID `json:"bidder_id"`
-	BidAmount float64   `json:"bid_amount"`
+	UserID    uuid.UUID `json:"user_id"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type AuctionInviteLink struct {
+	ID        uuid.UUID          `json:"id"`
+	ProductID uuid.UUID          `json:"product_id"`
+	TokenHash []byte             `json:"token_hash"`
+	MaxUses   pgtype.Int4        `json:"max_uses"`
+	UseCount  int32              `json:"use_count"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type AuthAuditLog struct {
+	ID        uuid.UUID   `json:"id"`
+	UserID    pgtype.UUID `json:"user_id"`
+	Email     string      `json:"email"`
+	IpAddress string      `json:"ip_address"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type Bid struct {
+	ID         uuid.UUID          `json:"id"`
+	ProductID  uuid.UUID          `json:"product_id"`
+	BidderID   uuid.UUID          `json:"bidder_id"`
+	BidAmount  float64            `json:"bid_amount"`
+	CreatedAt  time.Time          `json:"created_at"`
+	VoidedAt   pgtype.Timestamptz `json:"voided_at"`
+	VoidReason pgtype.Text        `json:"void_reason"`
+}
+
+type Category struct {
+	ID        uuid.UUID   `json:"id"`
+	ParentID  pgtype.UUID `json:"parent_id"`
+	Name      string      `json:"name"`
+	Slug      string      `json:"slug"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type Feedback struct {
+	ID            uuid.UUID `json:"id"`
+	ProductID     uuid.UUID `json:"product_id"`
+	AuthorID      uuid.UUID `json:"author_id"`
+	RecipientID   uuid.UUID `json:"recipient_id"`
+	RecipientRole string    `json:"recipient_role"`
+	Score         int32     `json:"score"`
+	Comment       string    `json:"comment"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type LoginFailure struct {
+	Scope        string             `json:"scope"`
+	Subject      string             `json:"subject"`
+	FailedCount  int32              `json:"failed_count"`
+	LastFailedAt time.Time          `json:"last_failed_at"`
+	LockedUntil  pgtype.Timestamptz `json:"locked_until"`
+}
+
+type Notification struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Kind      string             `json:"kind"`
+	ProductID pgtype.UUID        `json:"product_id"`
+	Title     string             `json:"title"`
+	Body      string             `json:"body"`
+	ReadAt    pgtype.Timestamptz `json:"read_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type PasswordResetToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	TokenHash []byte             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type PersonalAccessToken struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Name        string             `json:"name"`
+	TokenPrefix string             `json:"token_prefix"`
+	TokenHash   []byte             `json:"token_hash"`
+	Scopes      []string           `json:"scopes"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	LastUsedAt  pgtype.Timestamptz `json:"last_used_at"`
+	RevokedAt   pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
 type Product struct {
-	ID          uuid.UUID `json:"id"`
-	SellerID    uuid.UUID `json:"seller_id"`
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
-	IsSold      bool      `json:"is_sold"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                          uuid.UUID          `json:"id"`
+	SellerID                    uuid.UUID          `json:"seller_id"`
+	ProductName                 string             `json:"product_name"`
+	Description                 string             `json:"description"`
+	BasePrice                   float64            `json:"base_price"`
+	AuctionEnd                  time.Time          `json:"auction_end"`
+	IsSold                      bool               `json:"is_sold"`
+	CreatedAt                   time.Time          `json:"created_at"`
+	UpdatedAt                   time.Time          `json:"updated_at"`
+	SearchVector                string             `json:"-"`
+	CategoryID                  pgtype.UUID        `json:"category_id"`
+	WithdrawnAt                 pgtype.Timestamptz `json:"withdrawn_at"`
+	WithdrawalReason            pgtype.Text        `json:"withdrawal_reason"`
+	BiddersRequireVerifiedEmail bool               `json:"bidders_require_verified_email"`
+	BiddersMinAccountAgeDays    pgtype.Int4        `json:"bidders_min_account_age_days"`
+	BiddersMinFeedbackScore     pgtype.Int4        `json:"bidders_min_feedback_score"`
+	BiddersInviteOnly           bool               `json:"bidders_invite_only"`
+	Visibility                  string             `json:"visibility"`
+}
+
+type ProductImage struct {
+	ID           uuid.UUID `json:"id"`
+	ProductID    uuid.UUID `json:"product_id"`
+	StorageKey   string    `json:"storage_key"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+	Position     int32     `json:"position"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ProductTag struct {
+	ProductID uuid.UUID `json:"product_id"`
+	TagID     uuid.UUID `json:"tag_id"`
+}
+
+type SavedSearch struct {
+	ID         uuid.UUID     `json:"id"`
+	UserID     uuid.UUID     `json:"user_id"`
+	Name       string        `json:"name"`
+	Query      string        `json:"query"`
+	TsQuery    string        `json:"ts_query"`
+	CategoryID pgtype.UUID   `json:"category_id"`
+	SellerID   pgtype.UUID   `json:"seller_id"`
+	MinPrice   pgtype.Float8 `json:"min_price"`
+	MaxPrice   pgtype.Float8 `json:"max_price"`
+	Frequency  string        `json:"frequency"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+type SavedSearchMatch struct {
+	SavedSearchID uuid.UUID          `json:"saved_search_id"`
+	ProductID     uuid.UUID          `json:"product_id"`
+	NotifiedAt    pgtype.Timestamptz `json:"notified_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type SellerBlock struct {
+	SellerID      uuid.UUID `json:"seller_id"`
+	BlockedUserID uuid.UUID `json:"blocked_user_id"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type Session struct {
@@ -36,12 +190,72 @@ type Session struct {
 	Expiry time.Time `json:"expiry"`
 }
 
+type ShillFlag struct {
+	ID         uuid.UUID          `json:"id"`
+	SellerID   uuid.UUID          `json:"seller_id"`
+	BidderID   uuid.UUID          `json:"bidder_id"`
+	ProductID  uuid.UUID          `json:"product_id"`
+	Reason     string             `json:"reason"`
+	Details    string             `json:"details"`
+	Status     string             `json:"status"`
+	ReviewedBy pgtype.UUID        `json:"reviewed_by"`
+	ReviewedAt pgtype.Timestamptz `json:"reviewed_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	UserName     string    `json:"user_name"`
-	Email        string    `json:"email"`
-	PasswordHash []byte    `json:"password_hash"`
-	Bio          string    `json:"bio"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                 uuid.UUID          `json:"id"`
+	UserName           string             `json:"user_name"`
+	Email              string             `json:"email"`
+	PasswordHash       []byte             `json:"password_hash"`
+	Bio                string             `json:"bio"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+	EmailVerifiedAt    pgtype.Timestamptz `json:"email_verified_at"`
+	VerificationSentAt pgtype.Timestamptz `json:"verification_sent_at"`
+	Role               string             `json:"role"`
+	SuspendedAt        pgtype.Timestamptz `json:"suspended_at"`
+	SuspensionReason   pgtype.Text        `json:"suspension_reason"`
+	DeletedAt          pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type UserRecoveryCode struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  []byte             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type UserSession struct {
+	ID         uuid.UUID   `json:"id"`
+	UserID     uuid.UUID   `json:"user_id"`
+	IpAddress  string      `json:"ip_address"`
+	UserAgent  string      `json:"user_agent"`
+	LastSeenAt time.Time   `json:"last_seen_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Token      pgtype.Text `json:"token"`
+	ExpiresAt  time.Time   `json:"expires_at"`
+}
+
+type UserTotp struct {
+	UserID                   uuid.UUID          `json:"user_id"`
+	Secret                   string             `json:"secret"`
+	ConfirmedAt              pgtype.Timestamptz `json:"confirmed_at"`
+	LastUsedStep             pgtype.Int8        `json:"last_used_step"`
+	BidConfirmationThreshold pgtype.Float8      `json:"bid_confirmation_threshold"`
+	CreatedAt                time.Time          `json:"created_at"`
+}
+
+type Watchlist struct {
+	UserID               uuid.UUID          `json:"user_id"`
+	ProductID            uuid.UUID          `json:"product_id"`
+	EndingSoonNotifiedAt pgtype.Timestamptz `json:"ending_soon_notified_at"`
+	CreatedAt            time.Time          `json:"created_at"`
 }
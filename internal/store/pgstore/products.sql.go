@@ -10,21 +10,24 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createProduct = `-- name: CreateProduct :one
 
-INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end")
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end", "category_id", "visibility")
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING id
 `
 
 type CreateProductParams struct {
-	SellerID    uuid.UUID `json:"seller_id"`
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	SellerID    uuid.UUID   `json:"seller_id"`
+	ProductName string      `json:"product_name"`
+	Description string      `json:"description"`
+	BasePrice   float64     `json:"base_price"`
+	AuctionEnd  time.Time   `json:"auction_end"`
+	CategoryID  pgtype.UUID `json:"category_id"`
+	Visibility  string      `json:"visibility"`
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (uuid.UUID, error) {
@@ -34,6 +37,8 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (u
 		arg.Description,
 		arg.BasePrice,
 		arg.AuctionEnd,
+		arg.CategoryID,
+		arg.Visibility,
 	)
 	var id uuid.UUID
 	err := row.Scan(&id)
@@ -42,7 +47,7 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (u
 
 const getProductById = `-- name: GetProductById :one
 
-SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at FROM products
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, search_vector, category_id, withdrawn_at, withdrawal_reason, bidders_require_verified_email, bidders_min_account_age_days, bidders_min_feedback_score, bidders_invite_only, visibility FROM products
 WHERE id = $1
 `
 
@@ -59,6 +64,960 @@ func (q *Queries) GetProductById(ctx context.Context, id uuid.UUID) (Product, er
 		&i.IsSold,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+		&i.BiddersRequireVerifiedEmail,
+		&i.BiddersMinAccountAgeDays,
+		&i.BiddersMinFeedbackScore,
+		&i.BiddersInviteOnly,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const getProductByIdForUpdate = `-- name: GetProductByIdForUpdate :one
+
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, search_vector, category_id, withdrawn_at, withdrawal_reason, bidders_require_verified_email, bidders_min_account_age_days, bidders_min_feedback_score, bidders_invite_only, visibility FROM products
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetProductByIdForUpdate(ctx context.Context, id uuid.UUID) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductByIdForUpdate, id)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+		&i.BiddersRequireVerifiedEmail,
+		&i.BiddersMinAccountAgeDays,
+		&i.BiddersMinFeedbackScore,
+		&i.BiddersInviteOnly,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const listProductsBySeller = `-- name: ListProductsBySeller :many
+SELECT id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, search_vector, category_id, withdrawn_at, withdrawal_reason, bidders_require_verified_email, bidders_min_account_age_days, bidders_min_feedback_score, bidders_invite_only, visibility FROM products
+WHERE seller_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListProductsBySeller(ctx context.Context, sellerID uuid.UUID) ([]Product, error) {
+	rows, err := q.db.Query(ctx, listProductsBySeller, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Product
+	for rows.Next() {
+		var i Product
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.SearchVector,
+			&i.CategoryID,
+			&i.WithdrawnAt,
+			&i.WithdrawalReason,
+			&i.BiddersRequireVerifiedEmail,
+			&i.BiddersMinAccountAgeDays,
+			&i.BiddersMinFeedbackScore,
+			&i.BiddersInviteOnly,
+			&i.Visibility,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsEndingSoonest = `-- name: ListProductsEndingSoonest :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags
+FROM products p
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE ($1::text IS NULL
+    OR ($1 = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR ($1 = 'ended' AND p.auction_end <= now())
+    OR ($1 = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, $2::uuid)
+  AND ($3::uuid IS NULL OR p.seller_id = $3)
+  AND ($4::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= $4)
+  AND ($5::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= $5)
+  AND ($6::timestamptz IS NULL OR p.auction_end >= $6)
+  AND ($7::timestamptz IS NULL OR p.auction_end <= $7)
+  AND ($8::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = $8
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND ($9::uuid IS NULL OR (p.auction_end, p.id) > ($10::timestamptz, $9::uuid))
+ORDER BY p.auction_end ASC, p.id ASC
+LIMIT $11
+`
+
+type ListProductsEndingSoonestParams struct {
+	Status           pgtype.Text        `json:"status"`
+	ViewerID         pgtype.UUID        `json:"viewer_id"`
+	SellerID         pgtype.UUID        `json:"seller_id"`
+	MinPrice         pgtype.Float8      `json:"min_price"`
+	MaxPrice         pgtype.Float8      `json:"max_price"`
+	EndingAfter      pgtype.Timestamptz `json:"ending_after"`
+	EndingBefore     pgtype.Timestamptz `json:"ending_before"`
+	CategoryID       pgtype.UUID        `json:"category_id"`
+	CursorID         pgtype.UUID        `json:"cursor_id"`
+	CursorAuctionEnd pgtype.Timestamptz `json:"cursor_auction_end"`
+	PageSize         int32              `json:"page_size"`
+}
+
+type ListProductsEndingSoonestRow struct {
+	ID          uuid.UUID   `json:"id"`
+	SellerID    uuid.UUID   `json:"seller_id"`
+	ProductName string      `json:"product_name"`
+	Description string      `json:"description"`
+	BasePrice   float64     `json:"base_price"`
+	AuctionEnd  time.Time   `json:"auction_end"`
+	IsSold      bool        `json:"is_sold"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	CategoryID  pgtype.UUID `json:"category_id"`
+	HighestBid  float64     `json:"highest_bid"`
+	BidCount    int64       `json:"bid_count"`
+	Tags        []string    `json:"tags"`
+}
+
+func (q *Queries) ListProductsEndingSoonest(ctx context.Context, arg ListProductsEndingSoonestParams) ([]ListProductsEndingSoonestRow, error) {
+	rows, err := q.db.Query(ctx, listProductsEndingSoonest,
+		arg.Status,
+		arg.ViewerID,
+		arg.SellerID,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.EndingAfter,
+		arg.EndingBefore,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorAuctionEnd,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsEndingSoonestRow
+	for rows.Next() {
+		var i ListProductsEndingSoonestRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CategoryID,
+			&i.HighestBid,
+			&i.BidCount,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsHighestBid = `-- name: ListProductsHighestBid :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags
+FROM products p
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE ($1::text IS NULL
+    OR ($1 = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR ($1 = 'ended' AND p.auction_end <= now())
+    OR ($1 = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, $2::uuid)
+  AND ($3::uuid IS NULL OR p.seller_id = $3)
+  AND ($4::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= $4)
+  AND ($5::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= $5)
+  AND ($6::timestamptz IS NULL OR p.auction_end >= $6)
+  AND ($7::timestamptz IS NULL OR p.auction_end <= $7)
+  AND ($8::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = $8
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND ($9::uuid IS NULL OR (s.highest_bid, p.id) < ($10::float, $9::uuid))
+ORDER BY s.highest_bid DESC, p.id DESC
+LIMIT $11
+`
+
+type ListProductsHighestBidParams struct {
+	Status           pgtype.Text        `json:"status"`
+	ViewerID         pgtype.UUID        `json:"viewer_id"`
+	SellerID         pgtype.UUID        `json:"seller_id"`
+	MinPrice         pgtype.Float8      `json:"min_price"`
+	MaxPrice         pgtype.Float8      `json:"max_price"`
+	EndingAfter      pgtype.Timestamptz `json:"ending_after"`
+	EndingBefore     pgtype.Timestamptz `json:"ending_before"`
+	CategoryID       pgtype.UUID        `json:"category_id"`
+	CursorID         pgtype.UUID        `json:"cursor_id"`
+	CursorHighestBid pgtype.Float8      `json:"cursor_highest_bid"`
+	PageSize         int32              `json:"page_size"`
+}
+
+type ListProductsHighestBidRow struct {
+	ID          uuid.UUID   `json:"id"`
+	SellerID    uuid.UUID   `json:"seller_id"`
+	ProductName string      `json:"product_name"`
+	Description string      `json:"description"`
+	BasePrice   float64     `json:"base_price"`
+	AuctionEnd  time.Time   `json:"auction_end"`
+	IsSold      bool        `json:"is_sold"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	CategoryID  pgtype.UUID `json:"category_id"`
+	HighestBid  float64     `json:"highest_bid"`
+	BidCount    int64       `json:"bid_count"`
+	Tags        []string    `json:"tags"`
+}
+
+func (q *Queries) ListProductsHighestBid(ctx context.Context, arg ListProductsHighestBidParams) ([]ListProductsHighestBidRow, error) {
+	rows, err := q.db.Query(ctx, listProductsHighestBid,
+		arg.Status,
+		arg.ViewerID,
+		arg.SellerID,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.EndingAfter,
+		arg.EndingBefore,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorHighestBid,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsHighestBidRow
+	for rows.Next() {
+		var i ListProductsHighestBidRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CategoryID,
+			&i.HighestBid,
+			&i.BidCount,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsMostBids = `-- name: ListProductsMostBids :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags
+FROM products p
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE ($1::text IS NULL
+    OR ($1 = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR ($1 = 'ended' AND p.auction_end <= now())
+    OR ($1 = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, $2::uuid)
+  AND ($3::uuid IS NULL OR p.seller_id = $3)
+  AND ($4::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= $4)
+  AND ($5::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= $5)
+  AND ($6::timestamptz IS NULL OR p.auction_end >= $6)
+  AND ($7::timestamptz IS NULL OR p.auction_end <= $7)
+  AND ($8::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = $8
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND ($9::uuid IS NULL OR (s.bid_count, p.id) < ($10::bigint, $9::uuid))
+ORDER BY s.bid_count DESC, p.id DESC
+LIMIT $11
+`
+
+type ListProductsMostBidsParams struct {
+	Status         pgtype.Text        `json:"status"`
+	ViewerID       pgtype.UUID        `json:"viewer_id"`
+	SellerID       pgtype.UUID        `json:"seller_id"`
+	MinPrice       pgtype.Float8      `json:"min_price"`
+	MaxPrice       pgtype.Float8      `json:"max_price"`
+	EndingAfter    pgtype.Timestamptz `json:"ending_after"`
+	EndingBefore   pgtype.Timestamptz `json:"ending_before"`
+	CategoryID     pgtype.UUID        `json:"category_id"`
+	CursorID       pgtype.UUID        `json:"cursor_id"`
+	CursorBidCount pgtype.Int8        `json:"cursor_bid_count"`
+	PageSize       int32              `json:"page_size"`
+}
+
+type ListProductsMostBidsRow struct {
+	ID          uuid.UUID   `json:"id"`
+	SellerID    uuid.UUID   `json:"seller_id"`
+	ProductName string      `json:"product_name"`
+	Description string      `json:"description"`
+	BasePrice   float64     `json:"base_price"`
+	AuctionEnd  time.Time   `json:"auction_end"`
+	IsSold      bool        `json:"is_sold"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	CategoryID  pgtype.UUID `json:"category_id"`
+	HighestBid  float64     `json:"highest_bid"`
+	BidCount    int64       `json:"bid_count"`
+	Tags        []string    `json:"tags"`
+}
+
+func (q *Queries) ListProductsMostBids(ctx context.Context, arg ListProductsMostBidsParams) ([]ListProductsMostBidsRow, error) {
+	rows, err := q.db.Query(ctx, listProductsMostBids,
+		arg.Status,
+		arg.ViewerID,
+		arg.SellerID,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.EndingAfter,
+		arg.EndingBefore,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorBidCount,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsMostBidsRow
+	for rows.Next() {
+		var i ListProductsMostBidsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CategoryID,
+			&i.HighestBid,
+			&i.BidCount,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProductsNewest = `-- name: ListProductsNewest :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags
+FROM products p
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE ($1::text IS NULL
+    OR ($1 = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR ($1 = 'ended' AND p.auction_end <= now())
+    OR ($1 = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, $2::uuid)
+  AND ($3::uuid IS NULL OR p.seller_id = $3)
+  AND ($4::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= $4)
+  AND ($5::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= $5)
+  AND ($6::timestamptz IS NULL OR p.auction_end >= $6)
+  AND ($7::timestamptz IS NULL OR p.auction_end <= $7)
+  AND ($8::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = $8
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND ($9::uuid IS NULL OR (p.created_at, p.id) < ($10::timestamptz, $9::uuid))
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT $11
+`
+
+type ListProductsNewestParams struct {
+	Status          pgtype.Text        `json:"status"`
+	ViewerID        pgtype.UUID        `json:"viewer_id"`
+	SellerID        pgtype.UUID        `json:"seller_id"`
+	MinPrice        pgtype.Float8      `json:"min_price"`
+	MaxPrice        pgtype.Float8      `json:"max_price"`
+	EndingAfter     pgtype.Timestamptz `json:"ending_after"`
+	EndingBefore    pgtype.Timestamptz `json:"ending_before"`
+	CategoryID      pgtype.UUID        `json:"category_id"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	PageSize        int32              `json:"page_size"`
+}
+
+type ListProductsNewestRow struct {
+	ID          uuid.UUID   `json:"id"`
+	SellerID    uuid.UUID   `json:"seller_id"`
+	ProductName string      `json:"product_name"`
+	Description string      `json:"description"`
+	BasePrice   float64     `json:"base_price"`
+	AuctionEnd  time.Time   `json:"auction_end"`
+	IsSold      bool        `json:"is_sold"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	CategoryID  pgtype.UUID `json:"category_id"`
+	HighestBid  float64     `json:"highest_bid"`
+	BidCount    int64       `json:"bid_count"`
+	Tags        []string    `json:"tags"`
+}
+
+func (q *Queries) ListProductsNewest(ctx context.Context, arg ListProductsNewestParams) ([]ListProductsNewestRow, error) {
+	rows, err := q.db.Query(ctx, listProductsNewest,
+		arg.Status,
+		arg.ViewerID,
+		arg.SellerID,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.EndingAfter,
+		arg.EndingBefore,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListProductsNewestRow
+	for rows.Next() {
+		var i ListProductsNewestRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CategoryID,
+			&i.HighestBid,
+			&i.BidCount,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markProductSold = `-- name: MarkProductSold :exec
+
+UPDATE products
+SET is_sold = true, updated_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkProductSold(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markProductSold, id)
+	return err
+}
+
+const searchProducts = `-- name: SearchProducts :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags,
+  ts_rank(p.search_vector, q.query)::float AS rank,
+  ts_headline('english', html_escape(p.product_name), q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+  ts_headline('english', html_escape(p.description), q.query, 'StartSel=<mark>, StopSel=</mark>, MinWords=10, MaxWords=30') AS description_snippet
+FROM products p
+CROSS JOIN (SELECT to_tsquery('english', $1) AS query) q
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE p.search_vector @@ q.query
+  AND ($2::text IS NULL
+    OR ($2 = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR ($2 = 'ended' AND p.auction_end <= now())
+    OR ($2 = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, $3::uuid)
+  AND ($4::uuid IS NULL OR p.seller_id = $4)
+  AND ($5::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= $5)
+  AND ($6::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= $6)
+  AND ($7::timestamptz IS NULL OR p.auction_end >= $7)
+  AND ($8::timestamptz IS NULL OR p.auction_end <= $8)
+  AND ($9::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = $9
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND ($10::uuid IS NULL OR (ts_rank(p.search_vector, q.query)::float, p.id) < ($11::float, $10::uuid))
+ORDER BY rank DESC, p.id DESC
+LIMIT $12
+`
+
+type SearchProductsParams struct {
+	Query        string             `json:"query"`
+	Status       pgtype.Text        `json:"status"`
+	ViewerID     pgtype.UUID        `json:"viewer_id"`
+	SellerID     pgtype.UUID        `json:"seller_id"`
+	MinPrice     pgtype.Float8      `json:"min_price"`
+	MaxPrice     pgtype.Float8      `json:"max_price"`
+	EndingAfter  pgtype.Timestamptz `json:"ending_after"`
+	EndingBefore pgtype.Timestamptz `json:"ending_before"`
+	CategoryID   pgtype.UUID        `json:"category_id"`
+	CursorID     pgtype.UUID        `json:"cursor_id"`
+	CursorRank   pgtype.Float8      `json:"cursor_rank"`
+	PageSize     int32              `json:"page_size"`
+}
+
+type SearchProductsRow struct {
+	ID                 uuid.UUID   `json:"id"`
+	SellerID           uuid.UUID   `json:"seller_id"`
+	ProductName        string      `json:"product_name"`
+	Description        string      `json:"description"`
+	BasePrice          float64     `json:"base_price"`
+	AuctionEnd         time.Time   `json:"auction_end"`
+	IsSold             bool        `json:"is_sold"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+	CategoryID         pgtype.UUID `json:"category_id"`
+	HighestBid         float64     `json:"highest_bid"`
+	BidCount           int64       `json:"bid_count"`
+	Tags               []string    `json:"tags"`
+	Rank               float64     `json:"rank"`
+	NameHighlight      string      `json:"name_highlight"`
+	DescriptionSnippet string      `json:"description_snippet"`
+}
+
+func (q *Queries) SearchProducts(ctx context.Context, arg SearchProductsParams) ([]SearchProductsRow, error) {
+	rows, err := q.db.Query(ctx, searchProducts,
+		arg.Query,
+		arg.Status,
+		arg.ViewerID,
+		arg.SellerID,
+		arg.MinPrice,
+		arg.MaxPrice,
+		arg.EndingAfter,
+		arg.EndingBefore,
+		arg.CategoryID,
+		arg.CursorID,
+		arg.CursorRank,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchProductsRow
+	for rows.Next() {
+		var i SearchProductsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.SellerID,
+			&i.ProductName,
+			&i.Description,
+			&i.BasePrice,
+			&i.AuctionEnd,
+			&i.IsSold,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CategoryID,
+			&i.HighestBid,
+			&i.BidCount,
+			&i.Tags,
+			&i.Rank,
+			&i.NameHighlight,
+			&i.DescriptionSnippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setProductEligibility = `-- name: SetProductEligibility :one
+
+UPDATE products
+SET
+  bidders_require_verified_email = $2,
+  bidders_min_account_age_days = $3,
+  bidders_min_feedback_score = $4,
+  bidders_invite_only = $5,
+  updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, search_vector, category_id, withdrawn_at, withdrawal_reason, bidders_require_verified_email, bidders_min_account_age_days, bidders_min_feedback_score, bidders_invite_only, visibility
+`
+
+type SetProductEligibilityParams struct {
+	ID                          uuid.UUID   `json:"id"`
+	BiddersRequireVerifiedEmail bool        `json:"bidders_require_verified_email"`
+	BiddersMinAccountAgeDays    pgtype.Int4 `json:"bidders_min_account_age_days"`
+	BiddersMinFeedbackScore     pgtype.Int4 `json:"bidders_min_feedback_score"`
+	BiddersInviteOnly           bool        `json:"bidders_invite_only"`
+}
+
+func (q *Queries) SetProductEligibility(ctx context.Context, arg SetProductEligibilityParams) (Product, error) {
+	row := q.db.QueryRow(ctx, setProductEligibility,
+		arg.ID,
+		arg.BiddersRequireVerifiedEmail,
+		arg.BiddersMinAccountAgeDays,
+		arg.BiddersMinFeedbackScore,
+		arg.BiddersInviteOnly,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+		&i.BiddersRequireVerifiedEmail,
+		&i.BiddersMinAccountAgeDays,
+		&i.BiddersMinFeedbackScore,
+		&i.BiddersInviteOnly,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const setProductVisibility = `-- name: SetProductVisibility :one
+
+UPDATE products
+SET visibility = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, search_vector, category_id, withdrawn_at, withdrawal_reason, bidders_require_verified_email, bidders_min_account_age_days, bidders_min_feedback_score, bidders_invite_only, visibility
+`
+
+type SetProductVisibilityParams struct {
+	ID         uuid.UUID `json:"id"`
+	Visibility string    `json:"visibility"`
+}
+
+func (q *Queries) SetProductVisibility(ctx context.Context, arg SetProductVisibilityParams) (Product, error) {
+	row := q.db.QueryRow(ctx, setProductVisibility, arg.ID, arg.Visibility)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+		&i.BiddersRequireVerifiedEmail,
+		&i.BiddersMinAccountAgeDays,
+		&i.BiddersMinFeedbackScore,
+		&i.BiddersInviteOnly,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const updateProduct = `-- name: UpdateProduct :one
+
+UPDATE products
+SET
+  product_name = $2,
+  description = $3,
+  base_price = $4,
+  auction_end = $5,
+  category_id = $6,
+  updated_at = now()
+WHERE id = $1
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, search_vector, category_id, withdrawn_at, withdrawal_reason, bidders_require_verified_email, bidders_min_account_age_days, bidders_min_feedback_score, bidders_invite_only, visibility
+`
+
+type UpdateProductParams struct {
+	ID          uuid.UUID   `json:"id"`
+	ProductName string      `json:"product_name"`
+	Description string      `json:"description"`
+	BasePrice   float64     `json:"base_price"`
+	AuctionEnd  time.Time   `json:"auction_end"`
+	CategoryID  pgtype.UUID `json:"category_id"`
+}
+
+func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, updateProduct,
+		arg.ID,
+		arg.ProductName,
+		arg.Description,
+		arg.BasePrice,
+		arg.AuctionEnd,
+		arg.CategoryID,
+	)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+		&i.BiddersRequireVerifiedEmail,
+		&i.BiddersMinAccountAgeDays,
+		&i.BiddersMinFeedbackScore,
+		&i.BiddersInviteOnly,
+		&i.Visibility,
+	)
+	return i, err
+}
+
+const withdrawProduct = `-- name: WithdrawProduct :one
+
+UPDATE products
+SET withdrawn_at = now(), withdrawal_reason = $2, updated_at = now()
+WHERE id = $1 AND withdrawn_at IS NULL
+RETURNING id, seller_id, product_name, description, base_price, auction_end, is_sold, created_at, updated_at, search_vector, category_id, withdrawn_at, withdrawal_reason, bidders_require_verified_email, bidders_min_account_age_days, bidders_min_feedback_score, bidders_invite_only, visibility
+`
+
+type WithdrawProductParams struct {
+	ID               uuid.UUID   `json:"id"`
+	WithdrawalReason pgtype.Text `json:"withdrawal_reason"`
+}
+
+func (q *Queries) WithdrawProduct(ctx context.Context, arg WithdrawProductParams) (Product, error) {
+	row := q.db.QueryRow(ctx, withdrawProduct, arg.ID, arg.WithdrawalReason)
+	var i Product
+	err := row.Scan(
+		&i.ID,
+		&i.SellerID,
+		&i.ProductName,
+		&i.Description,
+		&i.BasePrice,
+		&i.AuctionEnd,
+		&i.IsSold,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.SearchVector,
+		&i.CategoryID,
+		&i.WithdrawnAt,
+		&i.WithdrawalReason,
+		&i.BiddersRequireVerifiedEmail,
+		&i.BiddersMinAccountAgeDays,
+		&i.BiddersMinFeedbackScore,
+		&i.BiddersInviteOnly,
+		&i.Visibility,
 	)
 	return i, err
 }
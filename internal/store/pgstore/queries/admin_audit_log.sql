@@ -0,0 +1,11 @@
+-- name: CreateAdminAuditEntry :exec
+
+INSERT INTO admin_audit_log ("actor_id", "action", "target_id", "details")
+VALUES ($1, $2, $3, $4);
+
+-- name: ListAdminAuditEntries :many
+
+SELECT * FROM admin_audit_log
+WHERE (sqlc.narg('target_id')::uuid IS NULL OR target_id = sqlc.narg('target_id'))
+ORDER BY created_at DESC
+LIMIT @page_size;
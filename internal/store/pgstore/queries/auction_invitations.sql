@@ -0,0 +1,62 @@
+-- name: AddAuctionInvitation :exec
+
+INSERT INTO auction_invitations ("product_id", "user_id")
+VALUES ($1, $2)
+ON CONFLICT (product_id, user_id) DO NOTHING;
+
+-- name: RemoveAuctionInvitation :execrows
+
+DELETE FROM auction_invitations
+WHERE product_id = $1
+  AND user_id = $2;
+
+-- name: ListAuctionInvitations :many
+
+SELECT
+  ai.user_id,
+  u.user_name,
+  ai.created_at
+FROM auction_invitations ai
+JOIN users u ON u.id = ai.user_id
+WHERE ai.product_id = $1
+ORDER BY ai.created_at;
+
+-- name: IsUserInvited :one
+
+SELECT EXISTS (
+  SELECT 1 FROM auction_invitations
+  WHERE product_id = $1
+    AND user_id = $2
+);
+
+-- name: CreateAuctionInviteLink :one
+
+INSERT INTO auction_invite_links ("product_id", "token_hash", "max_uses", "expires_at")
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListAuctionInviteLinks :many
+
+SELECT * FROM auction_invite_links
+WHERE product_id = $1
+ORDER BY created_at DESC;
+
+-- name: GetAuctionInviteLinkForUpdate :one
+
+SELECT * FROM auction_invite_links
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: IncrementAuctionInviteLinkUses :exec
+
+UPDATE auction_invite_links
+SET use_count = use_count + 1
+WHERE id = $1;
+
+-- name: RevokeAuctionInviteLink :execrows
+
+UPDATE auction_invite_links
+SET revoked_at = now()
+WHERE id = $1
+  AND product_id = $2
+  AND revoked_at IS NULL;
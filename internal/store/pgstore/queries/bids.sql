@@ -8,11 +8,66 @@ RETURNING *;
 
 SELECT * FROM bids
 WHERE product_id = $1
+  AND voided_at IS NULL
 ORDER BY bid_amount DESC;
 
 -- name: GetHighestBidByProductId :one
 
 SELECT * FROM bids
 WHERE product_id = $1
+  AND voided_at IS NULL
 ORDER BY bid_amount DESC
-LIMIT 1;
\ No newline at end of file
+LIMIT 1;
+
+-- name: CountBidsByProductId :one
+
+SELECT COUNT(*) FROM bids
+WHERE product_id = $1
+  AND voided_at IS NULL;
+
+-- name: ListBidHistory :many
+
+WITH bidder_numbers AS (
+  SELECT
+    bidder_id,
+    ROW_NUMBER() OVER (ORDER BY MIN(created_at), bidder_id) AS bidder_number
+  FROM bids
+  WHERE product_id = @product_id
+    AND voided_at IS NULL
+  GROUP BY bidder_id
+)
+SELECT b.id, b.bid_amount, b.created_at, n.bidder_number
+FROM bids b
+JOIN bidder_numbers n ON n.bidder_id = b.bidder_id
+WHERE b.product_id = @product_id
+  AND b.voided_at IS NULL
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (b.created_at, b.id) < (sqlc.narg('cursor_created_at'), sqlc.narg('cursor_id')::uuid))
+ORDER BY b.created_at DESC, b.id DESC
+LIMIT @page_size;
+
+-- name: GetBidById :one
+
+SELECT * FROM bids
+WHERE id = $1;
+
+-- name: VoidBid :one
+
+UPDATE bids
+SET voided_at = now(), void_reason = $2
+WHERE id = $1
+  AND voided_at IS NULL
+RETURNING *;
+
+-- name: ListBidsByBidder :many
+SELECT
+  b.id,
+  b.product_id,
+  p.product_name,
+  b.bid_amount,
+  b.created_at,
+  b.voided_at
+FROM bids b
+JOIN products p ON p.id = b.product_id
+WHERE b.bidder_id = $1
+ORDER BY b.created_at DESC;
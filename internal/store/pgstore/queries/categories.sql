@@ -0,0 +1,37 @@
+-- name: CreateCategory :one
+
+INSERT INTO categories ("parent_id", "name", "slug")
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetCategoryById :one
+
+SELECT * FROM categories
+WHERE id = $1;
+
+-- name: ListCategories :many
+
+SELECT * FROM categories
+ORDER BY name;
+
+-- name: UpdateCategory :one
+
+UPDATE categories
+SET parent_id = $2, name = $3, slug = $4, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteCategory :execrows
+
+DELETE FROM categories
+WHERE id = $1;
+
+-- name: IsCategoryInSubtree :one
+
+WITH RECURSIVE subtree AS (
+  SELECT c.id FROM categories c WHERE c.id = sqlc.arg('root_id')
+  UNION ALL
+  SELECT c.id FROM categories c
+  JOIN subtree s ON c.parent_id = s.id
+)
+SELECT EXISTS (SELECT 1 FROM subtree WHERE subtree.id = sqlc.arg('category_id'));
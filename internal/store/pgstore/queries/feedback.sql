@@ -0,0 +1,95 @@
+-- name: GetSale :one
+
+SELECT
+  p.seller_id,
+  top.bidder_id AS buyer_id,
+  p.is_sold,
+  p.auction_end
+FROM products p
+JOIN LATERAL (
+  SELECT b.bidder_id FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+  ORDER BY b.bid_amount DESC
+  LIMIT 1
+) top ON true
+WHERE p.id = $1
+  AND (p.is_sold OR p.auction_end <= now())
+  AND p.withdrawn_at IS NULL;
+
+-- name: CreateFeedback :one
+
+INSERT INTO feedback ("product_id", "author_id", "recipient_id", "recipient_role", "score", "comment")
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListFeedbackForUser :many
+
+SELECT
+  f.id,
+  f.product_id,
+  p.product_name,
+  f.author_id,
+  u.user_name AS author_name,
+  f.recipient_id,
+  f.recipient_role,
+  f.score,
+  f.comment,
+  f.created_at,
+  p.seller_id AS product_seller_id,
+  p.visibility AS product_visibility
+FROM feedback f
+JOIN users u ON u.id = f.author_id
+JOIN products p ON p.id = f.product_id
+WHERE f.recipient_id = @recipient_id
+  AND (sqlc.narg('recipient_role')::text IS NULL OR f.recipient_role = sqlc.narg('recipient_role'))
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (f.created_at, f.id) < (sqlc.narg('cursor_created_at'), sqlc.narg('cursor_id')::uuid))
+ORDER BY f.created_at DESC, f.id DESC
+LIMIT @page_size;
+
+-- name: ListFeedbackForProduct :many
+
+SELECT
+  f.id,
+  f.product_id,
+  p.product_name,
+  f.author_id,
+  u.user_name AS author_name,
+  f.recipient_id,
+  f.recipient_role,
+  f.score,
+  f.comment,
+  f.created_at,
+  p.seller_id AS product_seller_id,
+  p.visibility AS product_visibility
+FROM feedback f
+JOIN users u ON u.id = f.author_id
+JOIN products p ON p.id = f.product_id
+WHERE f.product_id = $1
+ORDER BY f.created_at;
+
+-- name: GetUserReputation :one
+
+SELECT
+  count(*) FILTER (WHERE score > 0) AS positive,
+  count(*) FILTER (WHERE score = 0) AS neutral,
+  count(*) FILTER (WHERE score < 0) AS negative
+FROM feedback
+WHERE recipient_id = $1;
+
+-- name: ListFeedbackByAuthor :many
+
+SELECT
+  f.id,
+  f.product_id,
+  p.product_name,
+  f.recipient_id,
+  f.recipient_role,
+  f.score,
+  f.comment,
+  f.created_at
+FROM feedback f
+JOIN products p ON p.id = f.product_id
+WHERE f.author_id = $1
+ORDER BY f.created_at DESC;
@@ -0,0 +1,39 @@
+-- name: GetLoginFailure :one
+
+SELECT * FROM login_failures
+WHERE scope = $1 AND subject = $2;
+
+-- name: RecordLoginFailure :one
+
+INSERT INTO login_failures (scope, subject, failed_count, last_failed_at)
+VALUES (@scope, @subject, 1, now())
+ON CONFLICT (scope, subject) DO UPDATE
+SET
+  failed_count = CASE
+    WHEN login_failures.last_failed_at < @window_start::timestamptz THEN 1
+    ELSE login_failures.failed_count + 1
+  END,
+  last_failed_at = now()
+RETURNING *;
+
+-- name: LockLoginSubject :exec
+
+UPDATE login_failures
+SET locked_until = $3
+WHERE scope = $1 AND subject = $2;
+
+-- name: ClearLoginFailures :exec
+
+DELETE FROM login_failures
+WHERE scope = $1 AND subject = $2;
+
+-- name: CreateAuthAuditEntry :exec
+
+INSERT INTO auth_audit_log ("user_id", "email", "ip_address", "event")
+VALUES ($1, $2, $3, $4);
+
+-- name: DeleteAuthAuditEntries :exec
+
+DELETE FROM auth_audit_log
+WHERE user_id = @user_id
+  OR email = @email;
@@ -0,0 +1,100 @@
+-- name: CreateNotification :one
+
+INSERT INTO notifications ("user_id", "kind", "product_id", "title", "body")
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListNotifications :many
+
+SELECT * FROM notifications
+WHERE user_id = @user_id
+  AND (NOT @unread_only::bool OR read_at IS NULL)
+  AND (sqlc.narg('cursor_created_at')::timestamptz IS NULL
+    OR (created_at, id) < (sqlc.narg('cursor_created_at'), sqlc.narg('cursor_id')::uuid))
+ORDER BY created_at DESC, id DESC
+LIMIT @page_size;
+
+-- name: CountUnreadNotifications :one
+
+SELECT COUNT(*) FROM notifications
+WHERE user_id = $1
+  AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+
+UPDATE notifications
+SET read_at = now()
+WHERE user_id = $1
+  AND read_at IS NULL;
+
+-- name: GetOutbidBidder :one
+
+SELECT prev.bidder_id, p.product_name
+FROM products p
+JOIN LATERAL (
+  SELECT b.bidder_id FROM bids b
+  WHERE b.product_id = p.id
+    AND b.id <> @bid_id
+    AND b.voided_at IS NULL
+  ORDER BY b.bid_amount DESC
+  LIMIT 1
+) prev ON true
+WHERE p.id = @product_id
+  AND prev.bidder_id <> @bidder_id;
+
+-- name: ListAuctionBidders :many
+
+SELECT bidder_id, MAX(bid_amount)::float AS top_bid
+FROM bids
+WHERE product_id = $1
+  AND voided_at IS NULL
+GROUP BY bidder_id
+ORDER BY top_bid DESC;
+
+-- name: CreateAuctionEndNotification :one
+
+INSERT INTO notifications ("user_id", "kind", "product_id", "title", "body")
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, kind, product_id) WHERE kind IN ('auction_won', 'auction_lost', 'payment_due')
+DO NOTHING
+RETURNING *;
+
+-- name: ClaimEndedAuction :one
+
+INSERT INTO auction_end_notifications ("product_id")
+SELECT p.id FROM products p
+WHERE p.id = $1
+  AND p.auction_end <= now()
+  AND p.withdrawn_at IS NULL
+ON CONFLICT (product_id) DO NOTHING
+RETURNING product_id;
+
+-- name: ListUnannouncedEndedAuctions :many
+
+SELECT p.id FROM products p
+WHERE p.auction_end <= now()
+  AND p.withdrawn_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM auction_end_notifications n
+    WHERE n.product_id = p.id
+  )
+ORDER BY p.auction_end
+LIMIT $1;
+
+-- name: ListNotificationsByUser :many
+
+SELECT * FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteUserNotifications :exec
+
+DELETE FROM notifications
+WHERE user_id = $1;
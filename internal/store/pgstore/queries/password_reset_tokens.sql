@@ -0,0 +1,20 @@
+-- name: CreatePasswordResetToken :exec
+
+INSERT INTO password_reset_tokens ("user_id", "token_hash", "expires_at")
+VALUES ($1, $2, $3);
+
+-- name: ConsumePasswordResetToken :one
+
+UPDATE password_reset_tokens
+SET used_at = now()
+WHERE token_hash = $1
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING user_id;
+
+-- name: InvalidatePasswordResetTokens :exec
+
+UPDATE password_reset_tokens
+SET used_at = now()
+WHERE user_id = $1
+  AND used_at IS NULL;
@@ -0,0 +1,34 @@
+-- name: CreatePersonalAccessToken :one
+
+INSERT INTO personal_access_tokens ("user_id", "name", "token_prefix", "token_hash", "scopes", "expires_at")
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListPersonalAccessTokens :many
+
+SELECT * FROM personal_access_tokens
+WHERE user_id = $1
+  AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: RevokePersonalAccessToken :execrows
+
+UPDATE personal_access_tokens
+SET revoked_at = now()
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL;
+
+-- name: UsePersonalAccessToken :one
+
+UPDATE personal_access_tokens
+SET last_used_at = now()
+WHERE token_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > now())
+RETURNING user_id, scopes;
+
+-- name: DeletePersonalAccessTokens :exec
+
+DELETE FROM personal_access_tokens
+WHERE user_id = $1;
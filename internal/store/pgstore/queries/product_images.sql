@@ -0,0 +1,30 @@
+-- name: CreateProductImage :one
+
+INSERT INTO product_images ("id", "product_id", "storage_key", "thumbnail_key", "content_type", "width", "height", "position")
+VALUES ($1, $2, $3, $4, $5, $6, $7, (
+  SELECT COALESCE(MAX(position) + 1, 0)::integer FROM product_images WHERE product_id = $2
+))
+RETURNING *;
+
+-- name: CountProductImages :one
+
+SELECT COUNT(*) FROM product_images
+WHERE product_id = $1;
+
+-- name: GetProductImagesByProductId :many
+
+SELECT * FROM product_images
+WHERE product_id = $1
+ORDER BY position;
+
+-- name: GetProductImagesByProductIds :many
+
+SELECT * FROM product_images
+WHERE product_id = ANY(sqlc.arg('product_ids')::uuid[])
+ORDER BY product_id, position;
+
+-- name: DeleteProductImage :one
+
+DELETE FROM product_images
+WHERE id = $1 AND product_id = $2
+RETURNING *;
@@ -1,10 +1,341 @@
 -- name: CreateProduct :one
 
-INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end")
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO products ("seller_id", "product_name", "description", "base_price", "auction_end", "category_id", "visibility")
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 RETURNING id;
 
 -- name: GetProductById :one
 
 SELECT * FROM products
 WHERE id = $1;
+
+-- name: GetProductByIdForUpdate :one
+
+SELECT * FROM products
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateProduct :one
+
+UPDATE products
+SET
+  product_name = $2,
+  description = $3,
+  base_price = $4,
+  auction_end = $5,
+  category_id = $6,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: WithdrawProduct :one
+
+UPDATE products
+SET withdrawn_at = now(), withdrawal_reason = $2, updated_at = now()
+WHERE id = $1 AND withdrawn_at IS NULL
+RETURNING *;
+
+-- name: ListProductsEndingSoonest :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags
+FROM products p
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE (sqlc.narg('status')::text IS NULL
+    OR (sqlc.narg('status') = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR (sqlc.narg('status') = 'ended' AND p.auction_end <= now())
+    OR (sqlc.narg('status') = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, sqlc.narg('viewer_id')::uuid)
+  AND (sqlc.narg('seller_id')::uuid IS NULL OR p.seller_id = sqlc.narg('seller_id'))
+  AND (sqlc.narg('min_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= sqlc.narg('min_price'))
+  AND (sqlc.narg('max_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= sqlc.narg('max_price'))
+  AND (sqlc.narg('ending_after')::timestamptz IS NULL OR p.auction_end >= sqlc.narg('ending_after'))
+  AND (sqlc.narg('ending_before')::timestamptz IS NULL OR p.auction_end <= sqlc.narg('ending_before'))
+  AND (sqlc.narg('category_id')::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = sqlc.narg('category_id')
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (p.auction_end, p.id) > (sqlc.narg('cursor_auction_end')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY p.auction_end ASC, p.id ASC
+LIMIT sqlc.arg('page_size');
+
+-- name: ListProductsNewest :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags
+FROM products p
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE (sqlc.narg('status')::text IS NULL
+    OR (sqlc.narg('status') = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR (sqlc.narg('status') = 'ended' AND p.auction_end <= now())
+    OR (sqlc.narg('status') = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, sqlc.narg('viewer_id')::uuid)
+  AND (sqlc.narg('seller_id')::uuid IS NULL OR p.seller_id = sqlc.narg('seller_id'))
+  AND (sqlc.narg('min_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= sqlc.narg('min_price'))
+  AND (sqlc.narg('max_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= sqlc.narg('max_price'))
+  AND (sqlc.narg('ending_after')::timestamptz IS NULL OR p.auction_end >= sqlc.narg('ending_after'))
+  AND (sqlc.narg('ending_before')::timestamptz IS NULL OR p.auction_end <= sqlc.narg('ending_before'))
+  AND (sqlc.narg('category_id')::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = sqlc.narg('category_id')
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (p.created_at, p.id) < (sqlc.narg('cursor_created_at')::timestamptz, sqlc.narg('cursor_id')::uuid))
+ORDER BY p.created_at DESC, p.id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: ListProductsHighestBid :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags
+FROM products p
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE (sqlc.narg('status')::text IS NULL
+    OR (sqlc.narg('status') = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR (sqlc.narg('status') = 'ended' AND p.auction_end <= now())
+    OR (sqlc.narg('status') = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, sqlc.narg('viewer_id')::uuid)
+  AND (sqlc.narg('seller_id')::uuid IS NULL OR p.seller_id = sqlc.narg('seller_id'))
+  AND (sqlc.narg('min_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= sqlc.narg('min_price'))
+  AND (sqlc.narg('max_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= sqlc.narg('max_price'))
+  AND (sqlc.narg('ending_after')::timestamptz IS NULL OR p.auction_end >= sqlc.narg('ending_after'))
+  AND (sqlc.narg('ending_before')::timestamptz IS NULL OR p.auction_end <= sqlc.narg('ending_before'))
+  AND (sqlc.narg('category_id')::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = sqlc.narg('category_id')
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (s.highest_bid, p.id) < (sqlc.narg('cursor_highest_bid')::float, sqlc.narg('cursor_id')::uuid))
+ORDER BY s.highest_bid DESC, p.id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: ListProductsMostBids :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags
+FROM products p
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE (sqlc.narg('status')::text IS NULL
+    OR (sqlc.narg('status') = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR (sqlc.narg('status') = 'ended' AND p.auction_end <= now())
+    OR (sqlc.narg('status') = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, sqlc.narg('viewer_id')::uuid)
+  AND (sqlc.narg('seller_id')::uuid IS NULL OR p.seller_id = sqlc.narg('seller_id'))
+  AND (sqlc.narg('min_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= sqlc.narg('min_price'))
+  AND (sqlc.narg('max_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= sqlc.narg('max_price'))
+  AND (sqlc.narg('ending_after')::timestamptz IS NULL OR p.auction_end >= sqlc.narg('ending_after'))
+  AND (sqlc.narg('ending_before')::timestamptz IS NULL OR p.auction_end <= sqlc.narg('ending_before'))
+  AND (sqlc.narg('category_id')::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = sqlc.narg('category_id')
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (s.bid_count, p.id) < (sqlc.narg('cursor_bid_count')::bigint, sqlc.narg('cursor_id')::uuid))
+ORDER BY s.bid_count DESC, p.id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: SearchProducts :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.description,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.created_at,
+  p.updated_at,
+  p.category_id,
+  s.highest_bid,
+  s.bid_count,
+  ARRAY(
+    SELECT t.name FROM tags t
+    JOIN product_tags pt ON pt.tag_id = t.id
+    WHERE pt.product_id = p.id
+    ORDER BY t.name
+  )::text[] AS tags,
+  ts_rank(p.search_vector, q.query)::float AS rank,
+  ts_headline('english', html_escape(p.product_name), q.query, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+  ts_headline('english', html_escape(p.description), q.query, 'StartSel=<mark>, StopSel=</mark>, MinWords=10, MaxWords=30') AS description_snippet
+FROM products p
+CROSS JOIN (SELECT to_tsquery('english', sqlc.arg('query')) AS query) q
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE p.search_vector @@ q.query
+  AND (sqlc.narg('status')::text IS NULL
+    OR (sqlc.narg('status') = 'active' AND p.auction_end > now() AND NOT p.is_sold)
+    OR (sqlc.narg('status') = 'ended' AND p.auction_end <= now())
+    OR (sqlc.narg('status') = 'sold' AND p.is_sold))
+  AND p.withdrawn_at IS NULL
+  AND product_listed_for(p.id, p.seller_id, p.visibility, sqlc.narg('viewer_id')::uuid)
+  AND (sqlc.narg('seller_id')::uuid IS NULL OR p.seller_id = sqlc.narg('seller_id'))
+  AND (sqlc.narg('min_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) >= sqlc.narg('min_price'))
+  AND (sqlc.narg('max_price')::float IS NULL OR GREATEST(p.base_price, s.highest_bid) <= sqlc.narg('max_price'))
+  AND (sqlc.narg('ending_after')::timestamptz IS NULL OR p.auction_end >= sqlc.narg('ending_after'))
+  AND (sqlc.narg('ending_before')::timestamptz IS NULL OR p.auction_end <= sqlc.narg('ending_before'))
+  AND (sqlc.narg('category_id')::uuid IS NULL OR p.category_id IN (
+    WITH RECURSIVE subtree AS (
+      SELECT c.id FROM categories c WHERE c.id = sqlc.narg('category_id')
+      UNION ALL
+      SELECT c.id FROM categories c
+      JOIN subtree st ON c.parent_id = st.id
+    )
+    SELECT subtree.id FROM subtree
+  ))
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (ts_rank(p.search_vector, q.query)::float, p.id) < (sqlc.narg('cursor_rank')::float, sqlc.narg('cursor_id')::uuid))
+ORDER BY rank DESC, p.id DESC
+LIMIT sqlc.arg('page_size');
+
+-- name: ListProductsBySeller :many
+SELECT * FROM products
+WHERE seller_id = $1
+ORDER BY created_at DESC;
+
+-- name: SetProductEligibility :one
+
+UPDATE products
+SET
+  bidders_require_verified_email = $2,
+  bidders_min_account_age_days = $3,
+  bidders_min_feedback_score = $4,
+  bidders_invite_only = $5,
+  updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetProductVisibility :one
+
+UPDATE products
+SET visibility = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkProductSold :exec
+
+UPDATE products
+SET is_sold = true, updated_at = now()
+WHERE id = $1;
@@ -0,0 +1,115 @@
+-- name: CreateSavedSearch :one
+
+INSERT INTO saved_searches ("user_id", "name", "query", "ts_query", "category_id", "seller_id", "min_price", "max_price", "frequency")
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: ListSavedSearches :many
+
+SELECT * FROM saved_searches
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: CountSavedSearches :one
+
+SELECT COUNT(*) FROM saved_searches
+WHERE user_id = $1;
+
+-- name: UpdateSavedSearch :one
+
+UPDATE saved_searches
+SET name = $3, frequency = $4, updated_at = now()
+WHERE id = $1
+  AND user_id = $2
+RETURNING *;
+
+-- name: DeleteSavedSearch :execrows
+
+DELETE FROM saved_searches
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: MatchSavedSearches :exec
+
+WITH RECURSIVE product_categories AS (
+  SELECT c.id, c.parent_id FROM categories c
+  JOIN products p ON p.category_id = c.id
+  WHERE p.id = @product_id
+  UNION ALL
+  SELECT c.id, c.parent_id FROM categories c
+  JOIN product_categories pc ON c.id = pc.parent_id
+)
+INSERT INTO saved_search_matches ("saved_search_id", "product_id")
+SELECT ss.id, p.id
+FROM saved_searches ss
+JOIN products p ON p.id = @product_id
+WHERE ss.frequency <> 'off'
+  AND ss.user_id <> p.seller_id
+  AND p.visibility = 'public'
+  AND p.withdrawn_at IS NULL
+  AND (ss.ts_query = '' OR p.search_vector @@ to_tsquery('english', ss.ts_query))
+  AND (ss.category_id IS NULL OR ss.category_id IN (SELECT pc.id FROM product_categories pc))
+  AND (ss.seller_id IS NULL OR ss.seller_id = p.seller_id)
+  AND (ss.min_price IS NULL OR p.base_price >= ss.min_price)
+  AND (ss.max_price IS NULL OR p.base_price <= ss.max_price)
+  AND NOT EXISTS (
+    SELECT 1 FROM seller_blocks sb
+    WHERE sb.seller_id = p.seller_id
+      AND sb.blocked_user_id = ss.user_id
+  )
+ON CONFLICT (saved_search_id, product_id) DO NOTHING;
+
+-- name: ClaimInstantSearchMatches :many
+
+UPDATE saved_search_matches m
+SET notified_at = now()
+FROM saved_searches ss, users u, products p
+WHERE m.product_id = $1
+  AND m.notified_at IS NULL
+  AND ss.id = m.saved_search_id
+  AND ss.frequency = 'instant'
+  AND u.id = ss.user_id
+  AND u.deleted_at IS NULL
+  AND p.id = m.product_id
+  AND p.visibility = 'public'
+  AND p.withdrawn_at IS NULL
+  AND p.auction_end > now()
+  AND NOT EXISTS (
+    SELECT 1 FROM seller_blocks sb
+    WHERE sb.seller_id = p.seller_id
+      AND sb.blocked_user_id = ss.user_id
+  )
+RETURNING m.saved_search_id, ss.user_id, ss.name AS search_name, u.email, u.user_name, p.id AS product_id, p.product_name, p.base_price, p.auction_end;
+
+-- name: ClaimDigestSearchMatches :many
+
+UPDATE saved_search_matches m
+SET notified_at = now()
+FROM saved_searches ss, users u, products p
+WHERE m.notified_at IS NULL
+  AND ss.id = m.saved_search_id
+  AND ss.frequency <> 'off'
+  AND u.id = ss.user_id
+  AND u.deleted_at IS NULL
+  AND p.id = m.product_id
+  AND p.visibility = 'public'
+  AND p.withdrawn_at IS NULL
+  AND p.auction_end > now()
+  AND NOT EXISTS (
+    SELECT 1 FROM seller_blocks sb
+    WHERE sb.seller_id = p.seller_id
+      AND sb.blocked_user_id = ss.user_id
+  )
+RETURNING m.saved_search_id, ss.user_id, ss.name AS search_name, u.email, u.user_name, p.id AS product_id, p.product_name, p.base_price, p.auction_end;
+
+-- name: ReleaseSearchMatch :exec
+
+UPDATE saved_search_matches
+SET notified_at = NULL
+WHERE saved_search_id = $1
+  AND product_id = $2;
+
+-- name: DeleteUserSavedSearches :exec
+
+DELETE FROM saved_searches
+WHERE user_id = $1;
@@ -0,0 +1,7 @@
+-- name: ClaimScheduledJob :execrows
+
+INSERT INTO scheduled_jobs ("name", "last_run_at")
+VALUES (@name, now())
+ON CONFLICT (name) DO UPDATE
+SET last_run_at = now()
+WHERE scheduled_jobs.last_run_at <= @due_before::timestamptz;
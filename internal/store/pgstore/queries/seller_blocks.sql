@@ -0,0 +1,33 @@
+-- name: BlockUser :one
+
+INSERT INTO seller_blocks ("seller_id", "blocked_user_id", "reason")
+VALUES ($1, $2, $3)
+ON CONFLICT (seller_id, blocked_user_id) DO UPDATE
+SET reason = EXCLUDED.reason
+RETURNING *;
+
+-- name: UnblockUser :execrows
+
+DELETE FROM seller_blocks
+WHERE seller_id = $1
+  AND blocked_user_id = $2;
+
+-- name: ListBlockedUsers :many
+
+SELECT
+  sb.blocked_user_id,
+  u.user_name,
+  sb.reason,
+  sb.created_at
+FROM seller_blocks sb
+JOIN users u ON u.id = sb.blocked_user_id
+WHERE sb.seller_id = $1
+ORDER BY sb.created_at DESC;
+
+-- name: IsUserBlockedBySeller :one
+
+SELECT EXISTS (
+  SELECT 1 FROM seller_blocks
+  WHERE seller_id = $1
+    AND blocked_user_id = $2
+);
@@ -0,0 +1,87 @@
+-- name: FlagSharedIP :execrows
+
+WITH seller_ips AS (
+  SELECT s.ip_address FROM user_sessions s WHERE s.user_id = @seller_id
+  UNION
+  SELECT a.ip_address FROM auth_audit_log a
+  WHERE a.user_id = @seller_id
+    AND a.event = 'login_succeeded'
+),
+bidder_ips AS (
+  SELECT s.ip_address FROM user_sessions s WHERE s.user_id = @bidder_id
+  UNION
+  SELECT a.ip_address FROM auth_audit_log a
+  WHERE a.user_id = @bidder_id
+    AND a.event = 'login_succeeded'
+),
+shared AS (
+  SELECT ip_address FROM seller_ips
+  INTERSECT
+  SELECT ip_address FROM bidder_ips
+)
+INSERT INTO shill_flags (seller_id, bidder_id, product_id, reason, details)
+SELECT @seller_id::uuid, @bidder_id::uuid, @product_id::uuid, 'shared_ip',
+  'bidder and seller logged in from ' || string_agg(shared.ip_address, ', ')
+FROM shared
+HAVING count(*) > 0
+ON CONFLICT (seller_id, bidder_id, reason) DO NOTHING;
+
+-- name: FlagSingleSellerBidder :execrows
+
+WITH stats AS (
+  SELECT
+    count(*) AS bids,
+    count(DISTINCT b.product_id) AS products,
+    count(DISTINCT p.seller_id) AS sellers
+  FROM bids b
+  JOIN products p ON p.id = b.product_id
+  WHERE b.bidder_id = @bidder_id
+    AND b.voided_at IS NULL
+)
+INSERT INTO shill_flags (seller_id, bidder_id, product_id, reason, details)
+SELECT @seller_id::uuid, @bidder_id::uuid, @product_id::uuid, 'single_seller',
+  stats.bids || ' bids on ' || stats.products || ' auctions, all from this seller, none won'
+FROM stats
+WHERE stats.sellers = 1
+  AND stats.products >= @min_auctions::bigint
+  AND NOT EXISTS (
+    SELECT 1 FROM products p
+    JOIN LATERAL (
+      SELECT b.bidder_id FROM bids b
+      WHERE b.product_id = p.id
+        AND b.voided_at IS NULL
+      ORDER BY b.bid_amount DESC
+      LIMIT 1
+    ) top ON true
+    WHERE top.bidder_id = @bidder_id
+      AND p.withdrawn_at IS NULL
+      AND p.auction_end <= now()
+  )
+ON CONFLICT (seller_id, bidder_id, reason) DO NOTHING;
+
+-- name: IsConfirmedShill :one
+
+SELECT EXISTS (
+  SELECT 1 FROM shill_flags
+  WHERE seller_id = $1
+    AND bidder_id = $2
+    AND status = 'confirmed'
+);
+
+-- name: ListShillFlags :many
+
+SELECT * FROM shill_flags
+WHERE status = @status
+ORDER BY created_at
+LIMIT @page_size;
+
+-- name: ReviewShillFlag :one
+
+UPDATE shill_flags
+SET
+  status = @status,
+  reviewed_by = @reviewed_by,
+  reviewed_at = now()
+WHERE id = @id
+  AND status = 'open'
+RETURNING *;
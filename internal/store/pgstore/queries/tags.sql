@@ -0,0 +1,29 @@
+-- name: UpsertTag :one
+
+INSERT INTO tags ("name")
+VALUES ($1)
+ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+RETURNING id;
+
+-- name: ListTags :many
+
+SELECT * FROM tags
+ORDER BY name;
+
+-- name: DeleteTag :execrows
+
+DELETE FROM tags
+WHERE id = $1;
+
+-- name: AddProductTag :exec
+
+INSERT INTO product_tags ("product_id", "tag_id")
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: GetTagNamesByProductId :many
+
+SELECT t.name FROM tags t
+JOIN product_tags pt ON pt.tag_id = t.id
+WHERE pt.product_id = $1
+ORDER BY t.name;
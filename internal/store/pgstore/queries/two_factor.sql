@@ -0,0 +1,60 @@
+-- name: UpsertUserTOTP :exec
+
+INSERT INTO user_totp ("user_id", "secret")
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET
+  secret = EXCLUDED.secret,
+  confirmed_at = NULL,
+  last_used_step = NULL,
+  bid_confirmation_threshold = NULL,
+  created_at = now()
+WHERE user_totp.confirmed_at IS NULL;
+
+-- name: GetUserTOTP :one
+
+SELECT * FROM user_totp
+WHERE user_id = $1;
+
+-- name: ConfirmUserTOTP :exec
+
+UPDATE user_totp
+SET confirmed_at = now()
+WHERE user_id = $1;
+
+-- name: UseTOTPStep :execrows
+
+UPDATE user_totp
+SET last_used_step = @step::bigint
+WHERE user_id = @user_id
+  AND (last_used_step IS NULL OR last_used_step < @step);
+
+-- name: SetBidConfirmationThreshold :execrows
+
+UPDATE user_totp
+SET bid_confirmation_threshold = $2
+WHERE user_id = $1
+  AND confirmed_at IS NOT NULL;
+
+-- name: DeleteUserTOTP :exec
+
+DELETE FROM user_totp
+WHERE user_id = $1;
+
+-- name: CreateRecoveryCode :exec
+
+INSERT INTO user_recovery_codes ("user_id", "code_hash")
+VALUES ($1, $2);
+
+-- name: UseRecoveryCode :execrows
+
+UPDATE user_recovery_codes
+SET used_at = now()
+WHERE user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL;
+
+-- name: DeleteRecoveryCodes :exec
+
+DELETE FROM user_recovery_codes
+WHERE user_id = $1;
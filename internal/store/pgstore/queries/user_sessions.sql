@@ -0,0 +1,63 @@
+-- name: CreateUserSession :one
+
+INSERT INTO user_sessions ("user_id", "token", "expires_at", "ip_address", "user_agent")
+VALUES (@user_id, @token::text, @expires_at, @ip_address, @user_agent)
+RETURNING id;
+
+-- name: TouchUserSession :one
+
+WITH touched AS (
+  UPDATE user_sessions
+  SET
+    token = @token::text,
+    expires_at = @expires_at,
+    ip_address = @ip_address,
+    user_agent = @user_agent,
+    last_seen_at = now()
+  WHERE id = @id
+    AND user_id = @user_id
+    AND (last_seen_at < now() - interval '1 minute' OR token IS DISTINCT FROM @token::text)
+)
+SELECT EXISTS (
+  SELECT 1 FROM user_sessions
+  WHERE id = @id
+    AND user_id = @user_id
+) AS session_exists;
+
+-- name: ListUserSessions :many
+
+SELECT * FROM user_sessions
+WHERE user_id = $1
+  AND expires_at > now()
+ORDER BY last_seen_at DESC;
+
+-- name: DeleteUserSession :one
+
+WITH deleted AS (
+  DELETE FROM user_sessions
+  WHERE id = $1
+    AND user_id = $2
+  RETURNING token
+), destroyed AS (
+  DELETE FROM sessions
+  WHERE token IN (SELECT token FROM deleted)
+)
+SELECT COUNT(*) FROM deleted;
+
+-- name: DeleteUserSessions :many
+
+WITH deleted AS (
+  DELETE FROM user_sessions
+  WHERE user_id = @user_id
+    AND id <> @keep_id
+  RETURNING id, token
+), destroyed AS (
+  DELETE FROM sessions
+  WHERE token IN (SELECT token FROM deleted)
+)
+SELECT id FROM deleted;
+
+-- name: DeleteExpiredUserSessions :execrows
+
+DELETE FROM user_sessions
+WHERE expires_at < now();
@@ -12,7 +12,8 @@ SELECT
   email,
   bio,
   created_at,
-  updated_at
+  updated_at,
+  email_verified_at
 FROM users
 WHERE id = $1;
 
@@ -24,6 +25,177 @@ SELECT
   email,
   bio,
   created_at,
-  updated_at
+  updated_at,
+  suspended_at
 FROM users
 WHERE email = $1;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET
+  user_name = $2,
+  bio = $3,
+  updated_at = now()
+WHERE id = $1
+RETURNING
+  id,
+  user_name,
+  email,
+  bio,
+  created_at,
+  updated_at,
+  email_verified_at;
+
+-- name: GetSellerStats :one
+SELECT
+  COUNT(*) AS listed,
+  COUNT(*) FILTER (WHERE p.auction_end > now() AND NOT p.is_sold) AS active,
+  COUNT(*) FILTER (
+    WHERE (p.is_sold OR p.auction_end <= now())
+      AND EXISTS (SELECT 1 FROM bids b WHERE b.product_id = p.id AND b.voided_at IS NULL)
+  ) AS completed
+FROM products p
+WHERE p.seller_id = $1
+  AND p.withdrawn_at IS NULL;
+
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET
+  password_hash = $2,
+  updated_at = now()
+WHERE id = $1;
+
+-- name: IsUserEmailVerified :one
+SELECT email_verified_at IS NOT NULL AS verified
+FROM users
+WHERE id = $1;
+
+-- name: MarkUserEmailVerified :execrows
+UPDATE users
+SET
+  email_verified_at = COALESCE(email_verified_at, now()),
+  updated_at = now()
+WHERE id = $1
+  AND email = $2;
+
+-- name: ClaimVerificationEmail :one
+UPDATE users
+SET verification_sent_at = now()
+WHERE id = @id
+  AND email_verified_at IS NULL
+  AND (verification_sent_at IS NULL OR verification_sent_at < @sent_before::timestamptz)
+RETURNING user_name, email;
+
+-- name: GetUserAuthz :one
+SELECT role, suspended_at
+FROM users
+WHERE id = $1;
+
+-- name: CountAdmins :one
+SELECT COUNT(*)
+FROM users
+WHERE role = 'admin';
+
+-- name: UpdateUserRole :one
+UPDATE users u
+SET
+  role = @role,
+  updated_at = now()
+FROM (
+  SELECT id, role
+  FROM users
+  WHERE id = @id
+  FOR UPDATE
+) previous
+WHERE u.id = previous.id
+RETURNING previous.role AS previous_role;
+
+-- name: PromoteUserToSeller :execrows
+UPDATE users
+SET
+  role = 'seller',
+  updated_at = now()
+WHERE id = $1
+  AND role = 'user';
+
+-- name: SuspendUser :execrows
+UPDATE users
+SET
+  suspended_at = now(),
+  suspension_reason = $2,
+  updated_at = now()
+WHERE id = $1
+  AND suspended_at IS NULL;
+
+-- name: UnsuspendUser :execrows
+UPDATE users
+SET
+  suspended_at = NULL,
+  suspension_reason = NULL,
+  updated_at = now()
+WHERE id = $1
+  AND suspended_at IS NOT NULL;
+
+-- name: GetAccountDeletionBlockers :one
+SELECT
+  (
+    SELECT count(*) FROM products p
+    WHERE p.seller_id = @user_id
+      AND p.withdrawn_at IS NULL
+      AND p.auction_end > now()
+  ) AS open_auctions,
+  (
+    SELECT count(*) FROM products p
+    JOIN LATERAL (
+      SELECT b.bidder_id FROM bids b
+      WHERE b.product_id = p.id
+        AND b.voided_at IS NULL
+      ORDER BY b.bid_amount DESC
+      LIMIT 1
+    ) top ON true
+    WHERE top.bidder_id = @user_id
+      AND p.withdrawn_at IS NULL
+      AND p.auction_end > now()
+  ) AS leading_bids,
+  (
+    SELECT count(*) FROM products p
+    JOIN LATERAL (
+      SELECT b.bidder_id FROM bids b
+      WHERE b.product_id = p.id
+        AND b.voided_at IS NULL
+      ORDER BY b.bid_amount DESC
+      LIMIT 1
+    ) top ON true
+    WHERE top.bidder_id = @user_id
+      AND p.withdrawn_at IS NULL
+      AND NOT p.is_sold
+      AND p.auction_end <= now()
+  ) AS unsettled_wins,
+  (
+    SELECT count(*) FROM products p
+    WHERE p.seller_id = @user_id
+      AND p.withdrawn_at IS NULL
+      AND NOT p.is_sold
+      AND p.auction_end <= now()
+      AND EXISTS (
+        SELECT 1 FROM bids b
+        WHERE b.product_id = p.id
+          AND b.voided_at IS NULL
+      )
+  ) AS unsettled_sales;
+
+-- name: AnonymizeUser :execrows
+UPDATE users
+SET
+  user_name = 'deleted_' || replace(id::text, '-', ''),
+  email = replace(id::text, '-', '') || '@deleted.invalid',
+  password_hash = '',
+  bio = '',
+  email_verified_at = NULL,
+  verification_sent_at = NULL,
+  suspended_at = NULL,
+  suspension_reason = NULL,
+  deleted_at = now(),
+  updated_at = now()
+WHERE id = $1
+  AND deleted_at IS NULL;
@@ -0,0 +1,80 @@
+-- name: WatchProduct :exec
+
+INSERT INTO watchlist ("user_id", "product_id")
+VALUES ($1, $2)
+ON CONFLICT (user_id, product_id) DO NOTHING;
+
+-- name: UnwatchProduct :execrows
+
+DELETE FROM watchlist
+WHERE user_id = $1
+  AND product_id = $2;
+
+-- name: ListWatchlist :many
+
+SELECT
+  p.id,
+  p.seller_id,
+  p.product_name,
+  p.base_price,
+  p.auction_end,
+  p.is_sold,
+  p.withdrawn_at,
+  s.highest_bid,
+  s.bid_count,
+  w.created_at AS watched_at
+FROM watchlist w
+JOIN products p ON p.id = w.product_id
+CROSS JOIN LATERAL (
+  SELECT
+    COALESCE(MAX(b.bid_amount), 0)::float AS highest_bid,
+    COUNT(b.id) AS bid_count
+  FROM bids b
+  WHERE b.product_id = p.id
+    AND b.voided_at IS NULL
+) s
+WHERE w.user_id = @user_id
+  AND (p.visibility <> 'invite_only'
+    OR p.seller_id = @user_id
+    OR EXISTS (
+      SELECT 1 FROM auction_invitations ai
+      WHERE ai.product_id = p.id
+        AND ai.user_id = @user_id
+    ))
+ORDER BY (p.auction_end <= now()), p.auction_end;
+
+-- name: CountProductWatchers :one
+
+SELECT COUNT(*) FROM watchlist
+WHERE product_id = $1;
+
+-- name: ClaimEndingSoonWatches :many
+
+UPDATE watchlist w
+SET ending_soon_notified_at = now()
+FROM products p, users u
+WHERE p.id = w.product_id
+  AND u.id = w.user_id
+  AND w.ending_soon_notified_at IS NULL
+  AND p.withdrawn_at IS NULL
+  AND NOT p.is_sold
+  AND p.auction_end > now()
+  AND p.auction_end <= @ending_before::timestamptz
+  AND u.deleted_at IS NULL
+RETURNING w.user_id, w.product_id, u.email, u.user_name, p.product_name, p.auction_end;
+
+-- name: ListWatchlistByUser :many
+
+SELECT
+  w.product_id,
+  p.product_name,
+  w.created_at
+FROM watchlist w
+JOIN products p ON p.id = w.product_id
+WHERE w.user_id = $1
+ORDER BY w.created_at DESC;
+
+-- name: DeleteUserWatchlist :exec
+
+DELETE FROM watchlist
+WHERE user_id = $1;
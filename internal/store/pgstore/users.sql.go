@@ -10,8 +10,76 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const anonymizeUser = `-- name: AnonymizeUser :execrows
+UPDATE users
+SET
+  user_name = 'deleted_' || replace(id::text, '-', ''),
+  email = replace(id::text, '-', '') || '@deleted.invalid',
+  password_hash = '',
+  bio = '',
+  email_verified_at = NULL,
+  verification_sent_at = NULL,
+  suspended_at = NULL,
+  suspension_reason = NULL,
+  deleted_at = now(),
+  updated_at = now()
+WHERE id = $1
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) AnonymizeUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, anonymizeUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const claimVerificationEmail = `-- name: ClaimVerificationEmail :one
+UPDATE users
+SET verification_sent_at = now()
+WHERE id = $1
+  AND email_verified_at IS NULL
+  AND (verification_sent_at IS NULL OR verification_sent_at < $2::timestamptz)
+RETURNING user_name, email
+`
+
+type ClaimVerificationEmailParams struct {
+	ID         uuid.UUID `json:"id"`
+	SentBefore time.Time `json:"sent_before"`
+}
+
+type ClaimVerificationEmailRow struct {
+	UserName string `json:"user_name"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) ClaimVerificationEmail(ctx context.Context, arg ClaimVerificationEmailParams) (ClaimVerificationEmailRow, error) {
+	row := q.db.QueryRow(ctx, claimVerificationEmail, arg.ID, arg.SentBefore)
+	var i ClaimVerificationEmailRow
+	err := row.Scan(
+		&i.UserName,
+		&i.Email,
+	)
+	return i, err
+}
+
+const countAdmins = `-- name: CountAdmins :one
+SELECT COUNT(*)
+FROM users
+WHERE role = 'admin'
+`
+
+func (q *Queries) CountAdmins(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countAdmins)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUser = `-- name: CreateUser :one
 
 INSERT INTO users ("user_name", "email", "password_hash", "bio")
@@ -38,6 +106,125 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (uuid.UU
 	return id, err
 }
 
+const getAccountDeletionBlockers = `-- name: GetAccountDeletionBlockers :one
+SELECT
+  (
+    SELECT count(*) FROM products p
+    WHERE p.seller_id = $1
+      AND p.withdrawn_at IS NULL
+      AND p.auction_end > now()
+  ) AS open_auctions,
+  (
+    SELECT count(*) FROM products p
+    JOIN LATERAL (
+      SELECT b.bidder_id FROM bids b
+      WHERE b.product_id = p.id
+        AND b.voided_at IS NULL
+      ORDER BY b.bid_amount DESC
+      LIMIT 1
+    ) top ON true
+    WHERE top.bidder_id = $1
+      AND p.withdrawn_at IS NULL
+      AND p.auction_end > now()
+  ) AS leading_bids,
+  (
+    SELECT count(*) FROM products p
+    JOIN LATERAL (
+      SELECT b.bidder_id FROM bids b
+      WHERE b.product_id = p.id
+        AND b.voided_at IS NULL
+      ORDER BY b.bid_amount DESC
+      LIMIT 1
+    ) top ON true
+    WHERE top.bidder_id = $1
+      AND p.withdrawn_at IS NULL
+      AND NOT p.is_sold
+      AND p.auction_end <= now()
+  ) AS unsettled_wins,
+  (
+    SELECT count(*) FROM products p
+    WHERE p.seller_id = $1
+      AND p.withdrawn_at IS NULL
+      AND NOT p.is_sold
+      AND p.auction_end <= now()
+      AND EXISTS (
+        SELECT 1 FROM bids b
+        WHERE b.product_id = p.id
+          AND b.voided_at IS NULL
+      )
+  ) AS unsettled_sales
+`
+
+type GetAccountDeletionBlockersRow struct {
+	OpenAuctions   int64 `json:"open_auctions"`
+	LeadingBids    int64 `json:"leading_bids"`
+	UnsettledWins  int64 `json:"unsettled_wins"`
+	UnsettledSales int64 `json:"unsettled_sales"`
+}
+
+func (q *Queries) GetAccountDeletionBlockers(ctx context.Context, userID uuid.UUID) (GetAccountDeletionBlockersRow, error) {
+	row := q.db.QueryRow(ctx, getAccountDeletionBlockers, userID)
+	var i GetAccountDeletionBlockersRow
+	err := row.Scan(
+		&i.OpenAuctions,
+		&i.LeadingBids,
+		&i.UnsettledWins,
+		&i.UnsettledSales,
+	)
+	return i, err
+}
+
+const getSellerStats = `-- name: GetSellerStats :one
+SELECT
+  COUNT(*) AS listed,
+  COUNT(*) FILTER (WHERE p.auction_end > now() AND NOT p.is_sold) AS active,
+  COUNT(*) FILTER (
+    WHERE (p.is_sold OR p.auction_end <= now())
+      AND EXISTS (SELECT 1 FROM bids b WHERE b.product_id = p.id AND b.voided_at IS NULL)
+  ) AS completed
+FROM products p
+WHERE p.seller_id = $1
+  AND p.withdrawn_at IS NULL
+`
+
+type GetSellerStatsRow struct {
+	Listed    int64 `json:"listed"`
+	Active    int64 `json:"active"`
+	Completed int64 `json:"completed"`
+}
+
+func (q *Queries) GetSellerStats(ctx context.Context, sellerID uuid.UUID) (GetSellerStatsRow, error) {
+	row := q.db.QueryRow(ctx, getSellerStats, sellerID)
+	var i GetSellerStatsRow
+	err := row.Scan(
+		&i.Listed,
+		&i.Active,
+		&i.Completed,
+	)
+	return i, err
+}
+
+const getUserAuthz = `-- name: GetUserAuthz :one
+SELECT role, suspended_at
+FROM users
+WHERE id = $1
+`
+
+type GetUserAuthzRow struct {
+	Role        string             `json:"role"`
+	SuspendedAt pgtype.Timestamptz `json:"suspended_at"`
+}
+
+func (q *Queries) GetUserAuthz(ctx context.Context, id uuid.UUID) (GetUserAuthzRow, error) {
+	row := q.db.QueryRow(ctx, getUserAuthz, id)
+	var i GetUserAuthzRow
+	err := row.Scan(
+		&i.Role,
+		&i.SuspendedAt,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT 
   id,
@@ -46,19 +233,21 @@ SELECT
   email,
   bio,
   created_at,
-  updated_at
+  updated_at,
+  suspended_at
 FROM users
 WHERE email = $1
 `
 
 type GetUserByEmailRow struct {
-	ID           uuid.UUID `json:"id"`
-	UserName     string    `json:"user_name"`
-	PasswordHash []byte    `json:"password_hash"`
-	Email        string    `json:"email"`
-	Bio          string    `json:"bio"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID          `json:"id"`
+	UserName     string             `json:"user_name"`
+	PasswordHash []byte             `json:"password_hash"`
+	Email        string             `json:"email"`
+	Bio          string             `json:"bio"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	SuspendedAt  pgtype.Timestamptz `json:"suspended_at"`
 }
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
@@ -72,6 +261,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEm
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.SuspendedAt,
 	)
 	return i, err
 }
@@ -84,19 +274,21 @@ SELECT
   email,
   bio,
   created_at,
-  updated_at
+  updated_at,
+  email_verified_at
 FROM users
 WHERE id = $1
 `
 
 type GetUserByIdRow struct {
-	ID           uuid.UUID `json:"id"`
-	UserName     string    `json:"user_name"`
-	PasswordHash []byte    `json:"password_hash"`
-	Email        string    `json:"email"`
-	Bio          string    `json:"bio"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              uuid.UUID          `json:"id"`
+	UserName        string             `json:"user_name"`
+	PasswordHash    []byte             `json:"password_hash"`
+	Email           string             `json:"email"`
+	Bio             string             `json:"bio"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
 }
 
 func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow, error) {
@@ -110,6 +302,193 @@ func (q *Queries) GetUserById(ctx context.Context, id uuid.UUID) (GetUserByIdRow
 		&i.Bio,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
+
+const isUserEmailVerified = `-- name: IsUserEmailVerified :one
+SELECT email_verified_at IS NOT NULL AS verified
+FROM users
+WHERE id = $1
+`
+
+func (q *Queries) IsUserEmailVerified(ctx context.Context, id uuid.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, isUserEmailVerified, id)
+	var verified bool
+	err := row.Scan(&verified)
+	return verified, err
+}
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :execrows
+UPDATE users
+SET
+  email_verified_at = COALESCE(email_verified_at, now()),
+  updated_at = now()
+WHERE id = $1
+  AND email = $2
+`
+
+type MarkUserEmailVerifiedParams struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markUserEmailVerified, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const promoteUserToSeller = `-- name: PromoteUserToSeller :execrows
+UPDATE users
+SET
+  role = 'seller',
+  updated_at = now()
+WHERE id = $1
+  AND role = 'user'
+`
+
+func (q *Queries) PromoteUserToSeller(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, promoteUserToSeller, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const suspendUser = `-- name: SuspendUser :execrows
+UPDATE users
+SET
+  suspended_at = now(),
+  suspension_reason = $2,
+  updated_at = now()
+WHERE id = $1
+  AND suspended_at IS NULL
+`
+
+type SuspendUserParams struct {
+	ID               uuid.UUID   `json:"id"`
+	SuspensionReason pgtype.Text `json:"suspension_reason"`
+}
+
+func (q *Queries) SuspendUser(ctx context.Context, arg SuspendUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, suspendUser, arg.ID, arg.SuspensionReason)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const unsuspendUser = `-- name: UnsuspendUser :execrows
+UPDATE users
+SET
+  suspended_at = NULL,
+  suspension_reason = NULL,
+  updated_at = now()
+WHERE id = $1
+  AND suspended_at IS NOT NULL
+`
+
+func (q *Queries) UnsuspendUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, unsuspendUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :exec
+UPDATE users
+SET
+  password_hash = $2,
+  updated_at = now()
+WHERE id = $1
+`
+
+type UpdateUserPasswordParams struct {
+	ID           uuid.UUID `json:"id"`
+	PasswordHash []byte    `json:"password_hash"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET
+  user_name = $2,
+  bio = $3,
+  updated_at = now()
+WHERE id = $1
+RETURNING
+  id,
+  user_name,
+  email,
+  bio,
+  created_at,
+  updated_at,
+  email_verified_at
+`
+
+type UpdateUserProfileParams struct {
+	ID       uuid.UUID `json:"id"`
+	UserName string    `json:"user_name"`
+	Bio      string    `json:"bio"`
+}
+
+type UpdateUserProfileRow struct {
+	ID              uuid.UUID          `json:"id"`
+	UserName        string             `json:"user_name"`
+	Email           string             `json:"email"`
+	Bio             string             `json:"bio"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (UpdateUserProfileRow, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile, arg.ID, arg.UserName, arg.Bio)
+	var i UpdateUserProfileRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserName,
+		&i.Email,
+		&i.Bio,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users u
+SET
+  role = $1,
+  updated_at = now()
+FROM (
+  SELECT id, role
+  FROM users
+  WHERE id = $2
+  FOR UPDATE
+) previous
+WHERE u.id = previous.id
+RETURNING previous.role AS previous_role
+`
+
+type UpdateUserRoleParams struct {
+	Role string    `json:"role"`
+	ID   uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (string, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.Role, arg.ID)
+	var previousRole string
+	err := row.Scan(&previousRole)
+	return previousRole, err
+}
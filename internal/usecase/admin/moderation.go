@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+// ReasonReq is the body of the moderation actions, the reason ends up in the
+// audit log and, for canceled auctions, is shown to the bidders.
+type ReasonReq struct {
+	Reason string `json:"reason"`
+}
+
+func (req ReasonReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Reason), "reason", "this field cannot be empty")
+	eval.CheckField(validator.MaxChars(req.Reason, 255), "reason", "must have at most 255 characters")
+	return eval
+}
+
+type ChangeRoleReq struct {
+	Role services.Role `json:"role"`
+}
+
+func (req ChangeRoleReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.PermittedValue(req.Role, services.Roles...), "role", "must be one of user, seller, moderator or admin")
+	return eval
+}
+
+type ReviewShillFlagReq struct {
+	Status string `json:"status"`
+}
+
+func (req ReviewShillFlagReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.PermittedValue(req.Status, services.ShillFlagDismissed, services.ShillFlagConfirmed), "status", "must be dismissed or confirmed")
+	return eval
+}
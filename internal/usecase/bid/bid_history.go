@@ -0,0 +1,43 @@
+package bid
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+type BidHistoryReq struct {
+	Cursor string
+	Limit  int32
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+func NewBidHistoryReq(query url.Values) (BidHistoryReq, validator.Evaluator) {
+	var eval validator.Evaluator
+	req := BidHistoryReq{
+		Cursor: query.Get("cursor"),
+		Limit:  defaultPageSize,
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.ParseInt(raw, 10, 32)
+		eval.CheckField(err == nil, "limit", "must be a number")
+		req.Limit = int32(limit)
+	}
+
+	for key, message := range req.Valid(context.Background()) {
+		eval.AddFieldError(key, message)
+	}
+	return req, eval
+}
+
+func (req BidHistoryReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.Limit > 0 && req.Limit <= maxPageSize, "limit", "must be between 1 and 100")
+	return eval
+}
@@ -0,0 +1,38 @@
+package category
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type CategoryReq struct {
+	ParentID *uuid.UUID `json:"parent_id"`
+	Name     string     `json:"name"`
+	Slug     string     `json:"slug"`
+}
+
+func (req CategoryReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(req.Name, 50), "name", "must have at most 50 characters")
+
+	eval.CheckField(validator.NotBlank(req.Slug), "slug", "this field cannot be blank")
+	eval.CheckField(validator.Matches(req.Slug, validator.SlugRx), "slug", "must contain only lowercase letters, numbers and dashes")
+	eval.CheckField(validator.MaxChars(req.Slug, 50), "slug", "must have at most 50 characters")
+
+	return eval
+}
+
+type TagReq struct {
+	Name string `json:"name"`
+}
+
+func (req TagReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(req.Name, 30), "name", "must have at most 30 characters")
+
+	return eval
+}
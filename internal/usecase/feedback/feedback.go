@@ -0,0 +1,66 @@
+package feedback
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+type LeaveFeedbackReq struct {
+	// Score is -1 for negative, 0 for neutral and 1 for positive.
+	Score   *int32 `json:"score"`
+	Comment string `json:"comment"`
+}
+
+func (req LeaveFeedbackReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.Score != nil && validator.PermittedValue(*req.Score, services.FeedbackNegative, services.FeedbackNeutral, services.FeedbackPositive),
+		"score", "must be -1, 0 or 1")
+	eval.CheckField(validator.NotBlank(req.Comment) && validator.MaxChars(req.Comment, 500),
+		"comment", "must have between 1 and 500 characters")
+
+	return eval
+}
+
+type ListFeedbackReq struct {
+	Role   string
+	Cursor string
+	Limit  int32
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// NewListFeedbackReq reads the optional "role" filter, buyer or seller, and
+// the pagination parameters.
+func NewListFeedbackReq(query url.Values) (ListFeedbackReq, validator.Evaluator) {
+	var eval validator.Evaluator
+	req := ListFeedbackReq{
+		Role:   query.Get("role"),
+		Cursor: query.Get("cursor"),
+		Limit:  defaultPageSize,
+	}
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.ParseInt(raw, 10, 32)
+		eval.CheckField(err == nil, "limit", "must be a number")
+		req.Limit = int32(limit)
+	}
+
+	for key, message := range req.Valid(context.Background()) {
+		eval.AddFieldError(key, message)
+	}
+	return req, eval
+}
+
+func (req ListFeedbackReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.Role == "" || validator.PermittedValue(services.FeedbackRole(req.Role), services.FeedbackForBuyer, services.FeedbackForSeller),
+		"role", "must be buyer or seller")
+	eval.CheckField(req.Limit > 0 && req.Limit <= maxPageSize, "limit", "must be between 1 and 100")
+	return eval
+}
@@ -4,19 +4,26 @@ import (
 	"context"
 	"time"
 
+	"github.com/erikgmatos/gobid/internal/services"
 	"github.com/erikgmatos/gobid/internal/validator"
 	"github.com/google/uuid"
 )
 
 type CreateProductReq struct {
-	SellerID    uuid.UUID `json:"seller_id"`
-	ProductName string    `json:"product_name"`
-	Description string    `json:"description"`
-	BasePrice   float64   `json:"base_price"`
-	AuctionEnd  time.Time `json:"auction_end"`
+	SellerID    uuid.UUID  `json:"seller_id"`
+	ProductName string     `json:"product_name"`
+	Description string     `json:"description"`
+	BasePrice   float64    `json:"base_price"`
+	AuctionEnd  time.Time  `json:"auction_end"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+	Tags        []string   `json:"tags"`
+	Visibility  string     `json:"visibility"`
 }
 
-const minAuctionDuration = 2 * time.Hour
+const (
+	minAuctionDuration = 2 * time.Hour
+	maxTags            = 10
+)
 
 func (req CreateProductReq) Valid(ctx context.Context) validator.Evaluator {
 	var eval validator.Evaluator
@@ -30,5 +37,13 @@ func (req CreateProductReq) Valid(ctx context.Context) validator.Evaluator {
 
 	eval.CheckField(time.Until(req.AuctionEnd) >= minAuctionDuration, "auction_end", "must be at least 2 hours duration")
 
+	eval.CheckField(len(req.Tags) <= maxTags, "tags", "must have at most 10 tags")
+	for _, tag := range req.Tags {
+		eval.CheckField(validator.NotBlank(tag) && validator.MaxChars(tag, 30), "tags", "each tag must have between 1 and 30 characters")
+	}
+
+	eval.CheckField(req.Visibility == "" || validator.PermittedValue(services.Visibility(req.Visibility), services.Visibilities...),
+		"visibility", "must be one of public, unlisted or invite_only")
+
 	return eval
 }
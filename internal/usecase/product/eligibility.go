@@ -0,0 +1,36 @@
+package product
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type EligibilityPolicyReq struct {
+	RequireVerifiedEmail bool   `json:"require_verified_email"`
+	MinAccountAgeDays    *int32 `json:"min_account_age_days"`
+	MinFeedbackScore     *int32 `json:"min_feedback_score"`
+	InviteOnly           bool   `json:"invite_only"`
+}
+
+func (req EligibilityPolicyReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.MinAccountAgeDays == nil || (*req.MinAccountAgeDays > 0 && *req.MinAccountAgeDays <= 3650),
+		"min_account_age_days", "must be between 1 and 3650, or null to turn it off")
+	eval.CheckField(req.MinFeedbackScore == nil || (*req.MinFeedbackScore > 0 && *req.MinFeedbackScore <= 10000),
+		"min_feedback_score", "must be between 1 and 10000, or null to turn it off")
+
+	return eval
+}
+
+type InviteBidderReq struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (req InviteBidderReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.UserID != uuid.Nil, "user_id", "this field cannot be empty")
+
+	return eval
+}
@@ -0,0 +1,107 @@
+package product
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type ListProductsReq struct {
+	Status       string
+	SellerID     *uuid.UUID
+	MinPrice     *float64
+	MaxPrice     *float64
+	EndingAfter  *time.Time
+	EndingBefore *time.Time
+	CategoryID   *uuid.UUID
+	Sort         string
+	Cursor       string
+	Limit        int32
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var (
+	productStatuses = []string{"active", "ended", "sold"}
+	productSorts    = []string{"ending_soonest", "newest", "highest_bid", "most_bids"}
+)
+
+// NewListProductsReq reads the catalog filters from the query string. Values
+// that cannot be parsed are reported in the returned evaluator.
+func NewListProductsReq(query url.Values) (ListProductsReq, validator.Evaluator) {
+	var eval validator.Evaluator
+	req := ListProductsReq{
+		Status: query.Get("status"),
+		Sort:   query.Get("sort"),
+		Cursor: query.Get("cursor"),
+		Limit:  defaultPageSize,
+	}
+	if req.Sort == "" {
+		req.Sort = "ending_soonest"
+	}
+
+	if raw := query.Get("seller_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		eval.CheckField(err == nil, "seller_id", "must be a valid uuid")
+		req.SellerID = &id
+	}
+	if raw := query.Get("category_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		eval.CheckField(err == nil, "category_id", "must be a valid uuid")
+		req.CategoryID = &id
+	}
+	req.MinPrice = parseFloatParam(query, "min_price", &eval)
+	req.MaxPrice = parseFloatParam(query, "max_price", &eval)
+	req.EndingAfter = parseTimeParam(query, "ending_after", &eval)
+	req.EndingBefore = parseTimeParam(query, "ending_before", &eval)
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.ParseInt(raw, 10, 32)
+		eval.CheckField(err == nil, "limit", "must be a number")
+		req.Limit = int32(limit)
+	}
+
+	for key, message := range req.Valid(context.Background()) {
+		eval.AddFieldError(key, message)
+	}
+	return req, eval
+}
+
+func (req ListProductsReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.Status == "" || validator.PermittedValue(req.Status, productStatuses...), "status", "must be one of active, ended or sold")
+	eval.CheckField(validator.PermittedValue(req.Sort, productSorts...), "sort", "must be one of ending_soonest, newest, highest_bid or most_bids")
+	eval.CheckField(req.MinPrice == nil || *req.MinPrice >= 0, "min_price", "must be greater or equal to 0")
+	eval.CheckField(req.MinPrice == nil || req.MaxPrice == nil || *req.MinPrice <= *req.MaxPrice, "max_price", "must be greater or equal to min_price")
+	eval.CheckField(req.EndingAfter == nil || req.EndingBefore == nil || req.EndingAfter.Before(*req.EndingBefore), "ending_before", "must be after ending_after")
+	eval.CheckField(req.Limit > 0 && req.Limit <= maxPageSize, "limit", "must be between 1 and 100")
+
+	return eval
+}
+
+func parseFloatParam(query url.Values, key string, eval *validator.Evaluator) *float64 {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	eval.CheckField(err == nil, key, "must be a number")
+	return &value
+}
+
+func parseTimeParam(query url.Values, key string, eval *validator.Evaluator) *time.Time {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	eval.CheckField(err == nil, key, "must be a RFC 3339 timestamp")
+	return &value
+}
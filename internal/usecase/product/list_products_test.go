@@ -0,0 +1,59 @@
+package product
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestNewListProductsReq(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		fields []string
+	}{
+		{"defaults", "", nil},
+		{"all filters", "status=active&sort=newest&min_price=10&max_price=20&limit=50&ending_after=2024-01-01T00:00:00Z&ending_before=2024-02-01T00:00:00Z", nil},
+		{"unknown status", "status=pending", []string{"status"}},
+		{"unknown sort", "sort=relevance", []string{"sort"}},
+		{"invalid seller id", "seller_id=42", []string{"seller_id"}},
+		{"invalid category id", "category_id=shoes", []string{"category_id"}},
+		{"price not a number", "min_price=cheap", []string{"min_price"}},
+		{"negative min price", "min_price=-1", []string{"min_price"}},
+		{"max price under min price", "min_price=20&max_price=10", []string{"max_price"}},
+		{"invalid timestamp", "ending_after=tomorrow", []string{"ending_after"}},
+		{"ending range reversed", "ending_after=2024-02-01T00:00:00Z&ending_before=2024-01-01T00:00:00Z", []string{"ending_before"}},
+		{"limit not a number", "limit=all", []string{"limit"}},
+		{"zero limit", "limit=0", []string{"limit"}},
+		{"limit over max", "limit=101", []string{"limit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, eval := NewListProductsReq(query)
+			if len(eval) != len(tt.fields) {
+				t.Fatalf("got errors %v, want %v", eval, tt.fields)
+			}
+			for _, field := range tt.fields {
+				if eval[field] == "" {
+					t.Errorf("missing error for %q in %v", field, eval)
+				}
+			}
+		})
+	}
+}
+
+func TestNewListProductsReqDefaults(t *testing.T) {
+	req, eval := NewListProductsReq(url.Values{})
+	if len(eval) != 0 {
+		t.Fatalf("unexpected errors: %v", eval)
+	}
+	if req.Sort != "ending_soonest" {
+		t.Errorf("Sort = %q, want ending_soonest", req.Sort)
+	}
+	if req.Limit != defaultPageSize {
+		t.Errorf("Limit = %d, want %d", req.Limit, defaultPageSize)
+	}
+}
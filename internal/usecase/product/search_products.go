@@ -0,0 +1,36 @@
+package product
+
+import (
+	"maps"
+	"net/url"
+	"strings"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+type SearchProductsReq struct {
+	ListProductsReq
+	Query string
+}
+
+// NewSearchProductsReq reads the search text from the "q" parameter and the
+// same filters accepted by the product listing. Results are always ranked by
+// relevance, so "sort" only accepts relevance.
+func NewSearchProductsReq(query url.Values) (SearchProductsReq, validator.Evaluator) {
+	sort := query.Get("sort")
+	if sort != "" {
+		query = maps.Clone(query)
+		query.Del("sort")
+	}
+	list, eval := NewListProductsReq(query)
+	eval.CheckField(sort == "" || sort == "relevance", "sort", "search results can only be sorted by relevance")
+	req := SearchProductsReq{
+		ListProductsReq: list,
+		Query:           strings.TrimSpace(query.Get("q")),
+	}
+
+	eval.CheckField(validator.NotBlank(req.Query), "q", "this field cannot be blank")
+	eval.CheckField(validator.MaxChars(req.Query, 100), "q", "must have at most 100 characters")
+
+	return req, eval
+}
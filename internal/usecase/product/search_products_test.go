@@ -0,0 +1,51 @@
+package product
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestNewSearchProductsReq(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  url.Values
+		fields []string
+	}{
+		{"query", url.Values{"q": {"watch"}}, nil},
+		{"relevance sort", url.Values{"q": {"watch"}, "sort": {"relevance"}}, nil},
+		{"listing sort", url.Values{"q": {"watch"}, "sort": {"newest"}}, []string{"sort"}},
+		{"unknown sort", url.Values{"q": {"watch"}, "sort": {"cheapest"}}, []string{"sort"}},
+		{"missing query", url.Values{}, []string{"q"}},
+		{"blank query", url.Values{"q": {"   "}}, []string{"q"}},
+		{"long query", url.Values{"q": {strings.Repeat("a", 101)}}, []string{"q"}},
+		{"listing filters", url.Values{"q": {"watch"}, "limit": {"0"}}, []string{"limit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, eval := NewSearchProductsReq(tt.query)
+			if len(eval) != len(tt.fields) {
+				t.Fatalf("got errors %v, want %v", eval, tt.fields)
+			}
+			for _, field := range tt.fields {
+				if eval[field] == "" {
+					t.Errorf("missing error for %q in %v", field, eval)
+				}
+			}
+		})
+	}
+}
+
+func TestNewSearchProductsReqKeepsQuery(t *testing.T) {
+	query := url.Values{"q": {"  vintage watch "}, "sort": {"relevance"}}
+	req, eval := NewSearchProductsReq(query)
+	if len(eval) != 0 {
+		t.Fatalf("unexpected errors: %v", eval)
+	}
+	if req.Query != "vintage watch" {
+		t.Errorf("Query = %q, want %q", req.Query, "vintage watch")
+	}
+	if query.Get("sort") != "relevance" {
+		t.Errorf("the caller's query was modified: %v", query)
+	}
+}
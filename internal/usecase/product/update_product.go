@@ -0,0 +1,49 @@
+package product
+
+import (
+	"context"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+// UpdateProductReq is a partial update, only the fields sent are changed.
+type UpdateProductReq struct {
+	ProductName *string    `json:"product_name"`
+	Description *string    `json:"description"`
+	BasePrice   *float64   `json:"base_price"`
+	AuctionEnd  *time.Time `json:"auction_end"`
+	CategoryID  *uuid.UUID `json:"category_id"`
+}
+
+func (req UpdateProductReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	if req.ProductName != nil {
+		eval.CheckField(validator.NotBlank(*req.ProductName), "product_name", "this field cannot be blank")
+	}
+	if req.Description != nil {
+		eval.CheckField(validator.MinChars(*req.Description, 10) &&
+			validator.MaxChars(*req.Description, 255), "description", "this field must have a lenght between 10 and 255 characters")
+	}
+	if req.BasePrice != nil {
+		eval.CheckField(*req.BasePrice > 0, "base_price", "this field must be greater than 0")
+	}
+	if req.AuctionEnd != nil {
+		eval.CheckField(time.Until(*req.AuctionEnd) >= minAuctionDuration, "auction_end", "must be at least 2 hours from now")
+	}
+	eval.CheckField(req.ProductName != nil || req.Description != nil || req.BasePrice != nil ||
+		req.AuctionEnd != nil || req.CategoryID != nil, "product", "at least one field must be changed")
+
+	return eval
+}
+
+type WithdrawProductReq struct {
+	Reason string `json:"reason"`
+}
+
+func (req WithdrawProductReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.MaxChars(req.Reason, 255), "reason", "must have at most 255 characters")
+	return eval
+}
@@ -0,0 +1,50 @@
+package product
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/services"
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+type SetVisibilityReq struct {
+	Visibility string `json:"visibility"`
+}
+
+func (req SetVisibilityReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.PermittedValue(services.Visibility(req.Visibility), services.Visibilities...),
+		"visibility", "must be one of public, unlisted or invite_only")
+
+	return eval
+}
+
+type CreateInviteLinkReq struct {
+	// MaxUses limits how many users can join with the link, null for no
+	// limit.
+	MaxUses *int32 `json:"max_uses"`
+	// ExpiresInHours defaults to services.DefaultInviteLinkTTL.
+	ExpiresInHours *int32 `json:"expires_in_hours"`
+}
+
+func (req CreateInviteLinkReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.MaxUses == nil || (*req.MaxUses > 0 && *req.MaxUses <= 1000),
+		"max_uses", "must be between 1 and 1000, or null for no limit")
+	maxHours := int32(services.MaxInviteLinkTTL.Hours())
+	eval.CheckField(req.ExpiresInHours == nil || (*req.ExpiresInHours > 0 && *req.ExpiresInHours <= maxHours),
+		"expires_in_hours", "must be between 1 and 720")
+
+	return eval
+}
+
+type RedeemInviteLinkReq struct {
+	Token string `json:"token"`
+}
+
+func (req RedeemInviteLinkReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Token), "token", "this field cannot be blank")
+
+	return eval
+}
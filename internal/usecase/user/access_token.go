@@ -0,0 +1,30 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+type CreateAccessTokenReq struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+var accessTokenScopes = []string{"read", "bid", "sell"}
+
+func (req CreateAccessTokenReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be empty")
+	eval.CheckField(validator.MaxChars(req.Name, 100), "name", "must have at most 100 characters")
+
+	eval.CheckField(len(req.Scopes) > 0, "scopes", "at least one scope is required")
+	for _, scope := range req.Scopes {
+		eval.CheckField(validator.PermittedValue(scope, accessTokenScopes...), "scopes", "each scope must be one of read, bid or sell")
+	}
+	eval.CheckField(req.ExpiresAt == nil || req.ExpiresAt.After(time.Now()), "expires_at", "must be in the future")
+
+	return eval
+}
@@ -0,0 +1,21 @@
+package user
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+type BlockUserReq struct {
+	UserID uuid.UUID `json:"user_id"`
+	Reason string    `json:"reason"`
+}
+
+func (req BlockUserReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.UserID != uuid.Nil, "user_id", "this field cannot be empty")
+	eval.CheckField(validator.MaxChars(req.Reason, 255), "reason", "must have at most 255 characters")
+
+	return eval
+}
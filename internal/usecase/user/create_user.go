@@ -25,6 +25,7 @@ func (req CreateUserReq) Valid(ctx context.Context) validator.Evaluator {
 		validator.MaxChars(req.Bio, 255), "bio", "this firl must be between 10 and 255")
 
 	eval.CheckField(validator.MinChars(req.Password, 8), "password", "password must be bigger than 8 chars")
+	eval.CheckField(validator.MaxBytes(req.Password, 72), "password", "password must have at most 72 bytes")
 
 	return eval
 
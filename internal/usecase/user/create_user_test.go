@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCreateUserReqValid(t *testing.T) {
+	valid := CreateUserReq{
+		UserName: "jane",
+		Email:    "jane@example.com",
+		Password: "correct horse",
+		Bio:      "collects vintage watches",
+	}
+	tests := []struct {
+		name   string
+		modify func(*CreateUserReq)
+		field  string
+	}{
+		{"valid", func(*CreateUserReq) {}, ""},
+		{"blank user name", func(r *CreateUserReq) { r.UserName = " " }, "user_name"},
+		{"invalid email", func(r *CreateUserReq) { r.Email = "jane" }, "email"},
+		{"short bio", func(r *CreateUserReq) { r.Bio = "hi there" }, "bio"},
+		{"long bio", func(r *CreateUserReq) { r.Bio = strings.Repeat("a", 256) }, "bio"},
+		{"short password", func(r *CreateUserReq) { r.Password = "1234567" }, "password"},
+		{"72 byte password", func(r *CreateUserReq) { r.Password = strings.Repeat("a", 72) }, ""},
+		{"73 byte password", func(r *CreateUserReq) { r.Password = strings.Repeat("a", 73) }, "password"},
+		{"multibyte password over 72 bytes", func(r *CreateUserReq) { r.Password = strings.Repeat("é", 40) }, "password"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			eval := req.Valid(context.Background())
+			if tt.field == "" {
+				if len(eval) != 0 {
+					t.Fatalf("unexpected errors: %v", eval)
+				}
+				return
+			}
+			if len(eval) != 1 || eval[tt.field] == "" {
+				t.Fatalf("got errors %v, want only %q", eval, tt.field)
+			}
+		})
+	}
+}
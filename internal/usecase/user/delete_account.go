@@ -0,0 +1,22 @@
+package user
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+// DeleteAccountReq confirms the deletion with the password, and a 2FA code
+// or recovery code when two-factor authentication is on.
+type DeleteAccountReq struct {
+	Password     string `json:"password"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+func (req DeleteAccountReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Password), "password", "this field cannot be empty")
+
+	return eval
+}
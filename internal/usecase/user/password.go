@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+type ChangePasswordReq struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (req ChangePasswordReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.CurrentPassword), "current_password", "this field cannot be empty")
+	eval.CheckField(validator.MinChars(req.NewPassword, 8), "new_password", "password must be bigger than 8 chars")
+	eval.CheckField(validator.MaxBytes(req.NewPassword, 72), "new_password", "password must have at most 72 bytes")
+	eval.CheckField(req.NewPassword != req.CurrentPassword, "new_password", "must be different from the current password")
+
+	return eval
+}
+
+type ForgotPasswordReq struct {
+	Email string `json:"email"`
+}
+
+func (req ForgotPasswordReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Email), "email", "this field cannot be empty")
+	eval.CheckField(validator.Matches(req.Email, validator.EmailRx), "email", "must be a valid email")
+
+	return eval
+}
+
+type ResetPasswordReq struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (req ResetPasswordReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Token), "token", "this field cannot be empty")
+	eval.CheckField(validator.MinChars(req.NewPassword, 8), "new_password", "password must be bigger than 8 chars")
+	eval.CheckField(validator.MaxBytes(req.NewPassword, 72), "new_password", "password must have at most 72 bytes")
+
+	return eval
+}
@@ -0,0 +1,54 @@
+package user
+
+import (
+	"context"
+	"strings"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+	"github.com/google/uuid"
+)
+
+var searchFrequencies = []string{"instant", "daily", "off"}
+
+type CreateSavedSearchReq struct {
+	Name       string     `json:"name"`
+	Query      string     `json:"query"`
+	CategoryID *uuid.UUID `json:"category_id"`
+	SellerID   *uuid.UUID `json:"seller_id"`
+	MinPrice   *float64   `json:"min_price"`
+	MaxPrice   *float64   `json:"max_price"`
+	Frequency  string     `json:"frequency"`
+}
+
+func (req CreateSavedSearchReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be empty")
+	eval.CheckField(validator.MaxChars(req.Name, 100), "name", "must have at most 100 characters")
+	eval.CheckField(validator.MaxChars(req.Query, 200), "query", "must have at most 200 characters")
+	eval.CheckField(
+		strings.TrimSpace(req.Query) != "" || req.CategoryID != nil || req.SellerID != nil,
+		"query", "a query, a category or a seller is required",
+	)
+	eval.CheckField(req.MinPrice == nil || *req.MinPrice >= 0, "min_price", "must not be negative")
+	eval.CheckField(req.MaxPrice == nil || *req.MaxPrice >= 0, "max_price", "must not be negative")
+	if req.MinPrice != nil && req.MaxPrice != nil {
+		eval.CheckField(*req.MinPrice <= *req.MaxPrice, "max_price", "must not be lower than min_price")
+	}
+	eval.CheckField(req.Frequency == "" || validator.PermittedValue(req.Frequency, searchFrequencies...), "frequency", "must be one of instant, daily or off")
+
+	return eval
+}
+
+type UpdateSavedSearchReq struct {
+	Name      string `json:"name"`
+	Frequency string `json:"frequency"`
+}
+
+func (req UpdateSavedSearchReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Name), "name", "this field cannot be empty")
+	eval.CheckField(validator.MaxChars(req.Name, 100), "name", "must have at most 100 characters")
+	eval.CheckField(validator.PermittedValue(req.Frequency, searchFrequencies...), "frequency", "must be one of instant, daily or off")
+
+	return eval
+}
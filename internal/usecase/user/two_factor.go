@@ -0,0 +1,41 @@
+package user
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code"`
+}
+
+func (req TwoFactorCodeReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Code), "code", "this field cannot be empty")
+
+	return eval
+}
+
+type LoginTwoFactorReq struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+func (req LoginTwoFactorReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(validator.NotBlank(req.Code) || validator.NotBlank(req.RecoveryCode), "code", "send a code or a recovery_code")
+
+	return eval
+}
+
+type BidConfirmationThresholdReq struct {
+	Amount *float64 `json:"amount"`
+}
+
+func (req BidConfirmationThresholdReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	eval.CheckField(req.Amount == nil || *req.Amount > 0, "amount", "must be greater than 0, or null to turn it off")
+
+	return eval
+}
@@ -0,0 +1,27 @@
+package user
+
+import (
+	"context"
+
+	"github.com/erikgmatos/gobid/internal/validator"
+)
+
+type UpdateProfileReq struct {
+	UserName *string `json:"user_name"`
+	Bio      *string `json:"bio"`
+}
+
+func (req UpdateProfileReq) Valid(ctx context.Context) validator.Evaluator {
+	var eval validator.Evaluator
+	if req.UserName != nil {
+		eval.CheckField(validator.NotBlank(*req.UserName), "user_name", "this field cannot be empty")
+		eval.CheckField(validator.MaxChars(*req.UserName, 50), "user_name", "must have at most 50 characters")
+	}
+	if req.Bio != nil {
+		eval.CheckField(validator.MinChars(*req.Bio, 10) &&
+			validator.MaxChars(*req.Bio, 255), "bio", "this field must be between 10 and 255")
+	}
+	eval.CheckField(req.UserName != nil || req.Bio != nil, "profile", "at least one field must be changed")
+
+	return eval
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"context"
 	"regexp"
+	"slices"
 	"strings"
 	"unicode/utf8"
 )
@@ -13,6 +14,8 @@ type Validator interface {
 
 var EmailRx = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
+var SlugRx = regexp.MustCompile("^[a-z0-9]+(?:-[a-z0-9]+)*$")
+
 type Evaluator map[string]string
 
 func (e *Evaluator) AddFieldError(key string, message string) {
@@ -39,6 +42,12 @@ func MaxChars(value string, n int) bool {
 	return utf8.RuneCountInString(value) <= n
 }
 
+// MaxBytes bounds the encoded length, bcrypt only accepts passwords of at
+// most 72 bytes.
+func MaxBytes(value string, n int) bool {
+	return len(value) <= n
+}
+
 func MinChars(value string, n int) bool {
 	return utf8.RuneCountInString(value) >= n
 }
@@ -46,3 +55,7 @@ func MinChars(value string, n int) bool {
 func Matches(value string, rx *regexp.Regexp) bool {
 	return rx.MatchString(value)
 }
+
+func PermittedValue[T comparable](value T, permittedValues ...T) bool {
+	return slices.Contains(permittedValues, value)
+}
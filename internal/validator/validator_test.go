@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotBlank(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"   ", false},
+		{"\t\n", false},
+		{"a", true},
+		{"  a  ", true},
+	}
+	for _, tt := range tests {
+		if got := NotBlank(tt.value); got != tt.want {
+			t.Errorf("NotBlank(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLengthChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(string, int) bool
+		value string
+		n     int
+		want  bool
+	}{
+		{"MaxChars under", MaxChars, "abc", 4, true},
+		{"MaxChars exact", MaxChars, "abcd", 4, true},
+		{"MaxChars over", MaxChars, "abcde", 4, false},
+		{"MaxChars counts runes", MaxChars, "ééé", 3, true},
+		{"MinChars under", MinChars, "abc", 4, false},
+		{"MinChars exact", MinChars, "abcd", 4, true},
+		{"MinChars counts runes", MinChars, "éééé", 5, false},
+		{"MaxBytes exact", MaxBytes, strings.Repeat("a", 72), 72, true},
+		{"MaxBytes over", MaxBytes, strings.Repeat("a", 73), 72, false},
+		{"MaxBytes counts bytes", MaxBytes, strings.Repeat("é", 37), 72, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.check(tt.value, tt.n); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		rx    string
+		want  bool
+	}{
+		{"email", "jane@example.com", "email", true},
+		{"email with plus", "jane+bids@mail.example.com", "email", true},
+		{"email without at", "jane.example.com", "email", false},
+		{"email without domain", "jane@", "email", false},
+		{"email with space", "jane doe@example.com", "email", false},
+		{"slug", "vintage-watches", "slug", true},
+		{"slug with digits", "cars-2024", "slug", true},
+		{"slug uppercase", "Vintage", "slug", false},
+		{"slug double dash", "vintage--watches", "slug", false},
+		{"slug trailing dash", "vintage-", "slug", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rx := EmailRx
+			if tt.rx == "slug" {
+				rx = SlugRx
+			}
+			if got := Matches(tt.value, rx); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermittedValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"daily", true},
+		{"instant", true},
+		{"weekly", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := PermittedValue(tt.value, "instant", "daily", "off"); got != tt.want {
+			t.Errorf("PermittedValue(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluatorKeepsFirstError(t *testing.T) {
+	var eval Evaluator
+	eval.CheckField(true, "name", "never added")
+	eval.CheckField(false, "name", "first")
+	eval.CheckField(false, "name", "second")
+	eval.AddFieldError("email", "invalid")
+
+	if len(eval) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(eval), eval)
+	}
+	if eval["name"] != "first" {
+		t.Errorf("name error = %q, want %q", eval["name"], "first")
+	}
+	if eval["email"] != "invalid" {
+		t.Errorf("email error = %q, want %q", eval["email"], "invalid")
+	}
+}